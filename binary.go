@@ -0,0 +1,59 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// Version of the binary encoding, written as the first byte so the format can
+// change without breaking previously stored maps.
+const binaryVersion = 1
+
+// ErrBinaryVersion is returned when decoding data written with an unknown
+// version of the binary encoding.
+var ErrBinaryVersion = errors.New("orderedmap: unsupported binary encoding version")
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is a version
+// byte followed by the key:value pairs in order encoded with encoding/gob, so
+// interface keys and values must hold types registered with gob.Register.
+func (om *OrderedMap[K, V]) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte(binaryVersion)
+
+	if err := gob.NewEncoder(&buffer).Encode(om.Items()); err != nil {
+		return nil, fmt.Errorf("orderedmap: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding data written
+// by MarshalBinary. As with UnmarshalJSON existing keys are kept and the
+// decoded ones are set on top of them, new keys in their encoded order.
+func (om *OrderedMap[K, V]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("orderedmap: empty binary data")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("%w %d", ErrBinaryVersion, data[0])
+	}
+
+	var pairs []Pair[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&pairs); err != nil {
+		return fmt.Errorf("orderedmap: %w", err)
+	}
+	om.SetAll(pairs)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the binary encoding, so maps can
+// be encoded with gob on their own or inside other values.
+func (om *OrderedMap[K, V]) GobEncode() ([]byte, error) {
+	return om.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the binary encoding
+func (om *OrderedMap[K, V]) GobDecode(data []byte) error {
+	return om.UnmarshalBinary(data)
+}
@@ -0,0 +1,103 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	om := New[string, int]()
+	for _, key := range []string{"z", "a", "", "m"} {
+		om.Set(key, len(om.table))
+	}
+
+	data, err := om.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != binaryVersion {
+		t.Error("Missing version header ", data[0])
+	}
+
+	decoded := New[string, int]()
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(om, intEq) {
+		t.Error(fmt.Sprintf("Expecting %v received %v", om, decoded))
+	}
+
+	// Decoding on top of existing keys keeps them
+	existing := New[string, int]()
+	existing.Set("m", 100)
+	existing.Set("x", 200)
+	if err := existing.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(existing) != "OrderedMap[m:3 x:200 z:0 a:1 :2]" {
+		t.Error("Invalid merged map ", existing)
+	}
+}
+
+type gobPoint struct {
+	X, Y int
+}
+
+func TestGobInterfaceTypes(t *testing.T) {
+	gob.Register(gobPoint{})
+
+	om := NewOrderedMap()
+	om.Set(gobPoint{1, 2}, "point key")
+	om.Set("point", gobPoint{3, 4})
+	om.Set(3, nil)
+
+	nested := New[string, *OrderedMap[int, string]]()
+	nested.Set("map", NewFromPairs(Pair[int, string]{2, "b"}, Pair[int, string]{1, "a"}))
+
+	// Maps as values of gob encoded structs
+	type checkpoint struct {
+		Any    *OrderedMap[interface{}, interface{}]
+		Nested *OrderedMap[string, *OrderedMap[int, string]]
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(checkpoint{om, nested}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded checkpoint
+	if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := fmt.Sprint(decoded.Any); result != "OrderedMap[{1 2}:point key point:{3 4} 3:<nil>]" {
+		t.Error("Invalid decoded map ", result)
+	}
+	if inner, _ := decoded.Nested.Get("map"); fmt.Sprint(inner) != "OrderedMap[2:b 1:a]" {
+		t.Error("Invalid decoded nested map ", inner)
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	om := New[string, int]()
+
+	if err := om.UnmarshalBinary(nil); err == nil {
+		t.Error("Expecting error decoding empty data")
+	}
+	if err := om.UnmarshalBinary([]byte{99, 1, 2}); !errors.Is(err, ErrBinaryVersion) {
+		t.Error("Expecting version error, received ", err)
+	}
+	if err := om.UnmarshalBinary([]byte{binaryVersion, 1, 2}); err == nil {
+		t.Error("Expecting error decoding invalid data")
+	}
+
+	// Unregistered interface types can't be encoded
+	type unregistered struct{ A int }
+	anyMap := NewOrderedMap()
+	anyMap.Set(1, unregistered{1})
+	if _, err := anyMap.MarshalBinary(); err == nil {
+		t.Error("Expecting error encoding an unregistered type")
+	}
+}
@@ -0,0 +1,114 @@
+package orderedmap
+
+import "iter"
+
+// Pair is a key:value pair, used to build and load maps in bulk
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// MergePolicy selects how Merge handles keys present in both maps
+type MergePolicy int
+
+const (
+	// MergeKeepExisting leaves the existing value and position unchanged
+	MergeKeepExisting MergePolicy = iota
+
+	// MergeOverwrite replaces the value, keeping the existing position
+	MergeOverwrite
+
+	// MergeOverwriteMove replaces the value and moves the key to the end, as
+	// if it had been deleted and inserted again.
+	MergeOverwriteMove
+)
+
+// NewWithCapacity creates an empty OrderedMap with space for capacity
+// elements, so they can be added without growing the hash table.
+func NewWithCapacity[K comparable, V any](capacity int) *OrderedMap[K, V] {
+	om := New[K, V]()
+	om.table = make(map[K]*node[K, V], capacity)
+	return om
+}
+
+// NewFromPairs creates an OrderedMap with the given pairs in order, when a
+// key is repeated the last value is kept at the position of the first.
+func NewFromPairs[K comparable, V any](pairs ...Pair[K, V]) *OrderedMap[K, V] {
+	om := NewWithCapacity[K, V](len(pairs))
+	om.SetAll(pairs)
+	return om
+}
+
+// FromMap creates an OrderedMap with the contents of a Go map, ordered as
+// the keys in keyOrder. keyOrder keys not in m are ignored, and m keys not
+// in keyOrder are added after the rest in the unspecified order of map
+// iteration.
+func FromMap[K comparable, V any](m map[K]V, keyOrder []K) *OrderedMap[K, V] {
+	om := NewWithCapacity[K, V](len(m))
+	for _, key := range keyOrder {
+		if value, ok := m[key]; ok {
+			om.Set(key, value)
+		}
+	}
+
+	if om.Len() < len(m) {
+		for key, value := range m {
+			if _, ok := om.table[key]; !ok {
+				om.Set(key, value)
+			}
+		}
+	}
+	return om
+}
+
+// Replace an empty table with one with space for n elements
+func (om *OrderedMap[K, V]) reserve(n int) {
+	om.init()
+	if len(om.table) == 0 && n > 0 {
+		om.table = make(map[K]*node[K, V], n)
+	}
+}
+
+// SetAll sets every pair in order, as calling Set for each of them
+func (om *OrderedMap[K, V]) SetAll(pairs []Pair[K, V]) {
+	om.reserve(len(pairs))
+	defer om.group()()
+	for _, pair := range pairs {
+		om.Set(pair.Key, pair.Value)
+	}
+}
+
+// SetSeq sets every key:value pair of a sequence in order, as calling Set for
+// each of them.
+func (om *OrderedMap[K, V]) SetSeq(seq iter.Seq2[K, V]) {
+	om.init()
+	defer om.group()()
+	for key, value := range seq {
+		om.Set(key, value)
+	}
+}
+
+// Merge adds the contents of other to the map, in the other map order. New
+// keys are inserted at the end, and keys present in both maps are handled
+// according to policy.
+func (om *OrderedMap[K, V]) Merge(other *OrderedMap[K, V], policy MergePolicy) {
+	if other == om {
+		// Merging a map into itself doesn't change it with any policy
+		return
+	}
+
+	om.reserve(other.Len())
+	defer om.group()()
+	for key, value := range other.All() {
+		node, ok := om.table[key]
+		switch {
+		case !ok:
+			om.insert(key, value, om.root)
+		case policy == MergeOverwrite:
+			om.update(node, value)
+		case policy == MergeOverwriteMove:
+			om.update(node, value)
+			om.move(node, om.root)
+		}
+	}
+}
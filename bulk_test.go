@@ -0,0 +1,89 @@
+package orderedmap
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+)
+
+func TestNewFromPairs(t *testing.T) {
+	om := NewFromPairs(
+		Pair[string, int]{"a", 1},
+		Pair[string, int]{"b", 2},
+		Pair[string, int]{"a", 3},
+	)
+	if om.String() != "OrderedMap[a:3 b:2]" {
+		t.Error("Invalid map from pairs ", om)
+	}
+
+	om.SetAll([]Pair[string, int]{{"c", 4}, {"b", 5}})
+	om.SetSeq(maps.All(map[string]int{"d": 6}))
+	if om.String() != "OrderedMap[a:3 b:5 c:4 d:6]" {
+		t.Error("Invalid map after SetAll ", om)
+	}
+
+	empty := NewWithCapacity[string, int](100)
+	if empty.Len() != 0 {
+		t.Error("Map with capacity isn't empty")
+	}
+	empty.SetAll([]Pair[string, int]{{"x", 1}})
+	if value, ok := empty.Get("x"); value != 1 || !ok {
+		t.Error("SetAll failed on an empty map")
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	om := FromMap(m, []string{"c", "z", "a", "b"})
+	if om.String() != "OrderedMap[c:3 a:1 b:2]" {
+		t.Error("Invalid key order ", om)
+	}
+
+	// Keys missing from the order are still added, after the rest
+	om = FromMap(m, []string{"b"})
+	if om.Len() != 3 {
+		t.Error("Missing keys ", om)
+	}
+	if key, _, _ := om.GetFirst(); key != "b" {
+		t.Error("Expecting b first, found ", key)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		policy   MergePolicy
+		expected string
+	}{
+		{MergeKeepExisting, "[a:1 b:2 c:3 d:40]"},
+		{MergeOverwrite, "[a:1 b:20 c:30 d:40]"},
+		{MergeOverwriteMove, "[a:1 c:30 b:20 d:40]"},
+	}
+
+	for _, test := range tests {
+		om := NewFromPairs(Pair[string, int]{"a", 1}, Pair[string, int]{"b", 2}, Pair[string, int]{"c", 3})
+		other := NewFromPairs(Pair[string, int]{"c", 30}, Pair[string, int]{"b", 20}, Pair[string, int]{"d", 40})
+		om.EnableIndex()
+		om.Merge(other, test.policy)
+
+		var pairs []string
+		for key, value := range om.All() {
+			pairs = append(pairs, fmt.Sprintf("%v:%v", key, value))
+		}
+		if fmt.Sprint(pairs) != test.expected {
+			t.Error(fmt.Sprintf("Policy %v: Expecting %v received %v", test.policy, test.expected, pairs))
+		}
+		if index, _ := om.IndexOf("d"); index != 3 {
+			t.Error("Index not updated by Merge ", index)
+		}
+		if other.Len() != 3 {
+			t.Error("Merge modified the other map")
+		}
+
+		// Merging into itself changes nothing
+		om.Merge(om, test.policy)
+		if om.Len() != 4 {
+			t.Error("Merging a map into itself changed it")
+		}
+	}
+}
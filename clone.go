@@ -0,0 +1,89 @@
+package orderedmap
+
+// Clone returns an independent copy of the map with the same order. Values
+// are copied by assignment, so values that are pointers, slices or maps are
+// shared by both maps, use CloneFunc to copy them too.
+func (om *OrderedMap[K, V]) Clone() *OrderedMap[K, V] {
+	return om.CloneFunc(nil)
+}
+
+// CloneFunc returns an independent copy of the map with the same order,
+// where each value is the result of calling copyValue with the original.
+func (om *OrderedMap[K, V]) CloneFunc(copyValue func(value V) V) *OrderedMap[K, V] {
+	clone := NewWithCapacity[K, V](om.Len())
+	clone.nestedJSON = om.nestedJSON
+	if om.index != nil {
+		clone.index = &rankIndex[K, V]{}
+	}
+	om.copyInto(clone, copyValue)
+	return clone
+}
+
+// CopyInto replaces the contents of dst with a copy of the map, reusing the
+// dst hash table and list nodes to avoid allocations. dst keeps its own
+// settings, so its index is rebuilt if it was enabled. Iterators over dst
+// are invalidated.
+func (om *OrderedMap[K, V]) CopyInto(dst *OrderedMap[K, V]) {
+	om.copyInto(dst, nil)
+}
+
+// CopyIntoFunc is CopyInto using copyValue to copy each value
+func (om *OrderedMap[K, V]) CopyIntoFunc(dst *OrderedMap[K, V], copyValue func(value V) V) {
+	om.copyInto(dst, copyValue)
+}
+
+func (om *OrderedMap[K, V]) copyInto(dst *OrderedMap[K, V], copyValue func(value V) V) {
+	if dst == om && copyValue == nil {
+		return
+	}
+	dst.init()
+	before := dst.resetStart()
+	if dst == om {
+		// Copying each value in place
+		for n := om.root.Next; n != om.root; n = n.Next {
+			n.Value = copyValue(n.Value)
+		}
+		om.resetEnd(before)
+		return
+	}
+
+	// Detach the dst list, its nodes are reused in order
+	free := dst.root.Next
+	dst.root.Next, dst.root.Prev = dst.root, dst.root
+	clear(dst.table)
+
+	if om.root != nil {
+		for n := om.root.Next; n != om.root; n = n.Next {
+			var c *node[K, V]
+			if free != dst.root {
+				c, free = free, free.Next
+				c.rank = nil
+			} else {
+				c = &node[K, V]{}
+			}
+
+			c.Key, c.Value = n.Key, n.Value
+			if copyValue != nil {
+				c.Value = copyValue(n.Value)
+			}
+
+			// The index is rebuilt at the end, so it is linked directly
+			c.Next, c.Prev = dst.root, dst.root.Prev
+			dst.root.Prev.Next = c
+			dst.root.Prev = c
+			dst.table[c.Key] = c
+		}
+	}
+
+	// Clear the leftover nodes so they don't keep keys and values alive
+	for free != dst.root {
+		next := free.Next
+		*free = node[K, V]{}
+		free = next
+	}
+
+	if dst.index != nil {
+		dst.index = newRankIndex(dst.root)
+	}
+	dst.resetEnd(before)
+}
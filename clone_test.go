@@ -0,0 +1,87 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	om := New[string, []int]()
+	om.Set("a", []int{1})
+	om.Set("b", []int{2})
+	om.EnableIndex()
+
+	clone := om.Clone()
+	clone.Set("c", []int{3})
+	clone.MoveFirst("b")
+	om.Delete("a")
+
+	if fmt.Sprint(iterKeys(clone.Iter())) != "[b a c]" || om.Len() != 1 {
+		t.Error("Clone isn't independent ", clone, om)
+	}
+	if index, ok := clone.IndexOf("c"); index != 2 || !ok {
+		t.Error("Clone index not updated ", index, ok)
+	}
+
+	// Shallow copies share the values, deep copies don't
+	value, _ := om.Get("b")
+	value[0] = 20
+	if value, _ := clone.Get("b"); value[0] != 20 {
+		t.Error("Clone values aren't shallow copies")
+	}
+
+	deep := om.CloneFunc(func(value []int) []int {
+		return append([]int(nil), value...)
+	})
+	value[0] = 200
+	if value, _ := deep.Get("b"); value[0] != 20 {
+		t.Error("CloneFunc didn't copy the values ", value)
+	}
+}
+
+func TestCopyInto(t *testing.T) {
+	src := New[int, int]()
+	for i := 0; i < 3; i++ {
+		src.Set(i, i*10)
+	}
+
+	// Larger destination, the extra nodes are dropped
+	dst := New[int, int]()
+	for i := 10; i < 15; i++ {
+		dst.Set(i, i)
+	}
+	dst.EnableIndex()
+	first := dst.root.Next
+
+	src.CopyInto(dst)
+	if fmt.Sprint(iterKeys(dst.Iter())) != "[0 1 2]" || dst.Len() != 3 {
+		t.Error("Invalid CopyInto result ", dst)
+	}
+	if dst.table[0] != first {
+		t.Error("CopyInto didn't reuse the destination nodes")
+	}
+	if _, _, ok := dst.GetAt(2); !ok {
+		t.Error("CopyInto didn't rebuild the index")
+	}
+
+	// Smaller destination, new nodes are allocated
+	small := New[int, int]()
+	small.Set(100, 100)
+	src.CopyIntoFunc(small, func(value int) int { return value + 1 })
+	if value, _ := small.Get(2); value != 21 || small.Len() != 3 {
+		t.Error("Invalid CopyIntoFunc result ", small)
+	}
+	if _, ok := small.Get(100); ok {
+		t.Error("CopyInto kept destination keys")
+	}
+
+	src.Set(3, 30)
+	if dst.Len() != 3 {
+		t.Error("Copy isn't independent")
+	}
+
+	src.CopyInto(src)
+	if src.Len() != 4 {
+		t.Error("Copying into itself changed the map")
+	}
+}
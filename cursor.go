@@ -0,0 +1,115 @@
+package orderedmap
+
+import "errors"
+
+// ErrNoCurrent is returned by Cursor operations that need a current element
+// when the cursor is off the map or its element was deleted.
+var ErrNoCurrent = errors.New("orderedmap: cursor has no current element")
+
+// Cursor is a bidirectional iterator that can modify the map at its current
+// position. A new cursor is off the map, Next moves it to the first element
+// and Prev to the last one, and moving past either end takes it off the map
+// again.
+//
+// Deleting the current element through the cursor leaves it in place, so
+// Next and Prev continue from the neighbours of the deleted element. The map
+// can also be modified directly with the same limitations as MapIterator.
+type Cursor[K comparable, V any] struct {
+	om   *OrderedMap[K, V]
+	curr *node[K, V]
+}
+
+// Cursor creates a cursor off the map
+func (om *OrderedMap[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{om: om, curr: om.root}
+}
+
+// Next moves the cursor to the next element, returns false if there is none
+// and the cursor is now off the map.
+func (c *Cursor[K, V]) Next() bool {
+	c.curr = c.curr.Next
+	return c.curr != c.om.root
+}
+
+// Prev moves the cursor to the previous element, returns false if there is
+// none and the cursor is now off the map.
+func (c *Cursor[K, V]) Prev() bool {
+	c.curr = c.curr.Prev
+	return c.curr != c.om.root
+}
+
+// Seek moves the cursor to a key, if the key is not in the map the cursor is
+// left unchanged and it returns false.
+func (c *Cursor[K, V]) Seek(key K) bool {
+	node, ok := c.om.table[key]
+	if ok {
+		c.curr = node
+	}
+	return ok
+}
+
+// Reset moves the cursor off the map
+func (c *Cursor[K, V]) Reset() {
+	c.curr = c.om.root
+}
+
+// Valid returns true if the cursor is on an element of the map
+func (c *Cursor[K, V]) Valid() bool {
+	return c.curr != c.om.root && c.om.table[c.curr.Key] == c.curr
+}
+
+// Key returns the current key, or the zero value if there is no current
+// element.
+func (c *Cursor[K, V]) Key() (key K) {
+	if c.Valid() {
+		key = c.curr.Key
+	}
+	return
+}
+
+// Value returns the current value, or the zero value if there is no current
+// element.
+func (c *Cursor[K, V]) Value() (value V) {
+	if c.Valid() {
+		value = c.curr.Value
+	}
+	return
+}
+
+// SetValue replaces the value of the current element
+func (c *Cursor[K, V]) SetValue(value V) error {
+	if !c.Valid() {
+		return ErrNoCurrent
+	}
+	c.om.update(c.curr, value)
+	return nil
+}
+
+// Delete removes the current element from the map, the cursor can keep
+// moving from its position.
+func (c *Cursor[K, V]) Delete() error {
+	if !c.Valid() {
+		return ErrNoCurrent
+	}
+	c.om.Delete(c.curr.Key)
+	return nil
+}
+
+// InsertBefore inserts a new key:value pair just before the current element,
+// the cursor doesn't move. Returns ErrKeyExists if the key is already in the
+// map.
+func (c *Cursor[K, V]) InsertBefore(key K, value V) error {
+	if !c.Valid() {
+		return ErrNoCurrent
+	}
+	return c.om.InsertBefore(c.curr.Key, key, value)
+}
+
+// InsertAfter inserts a new key:value pair just after the current element,
+// the cursor doesn't move so it will be the next one.
+func (c *Cursor[K, V]) InsertAfter(key K, value V) error {
+	if !c.Valid() {
+		return ErrNoCurrent
+	}
+	return c.om.InsertAfter(c.curr.Key, key, value)
+}
@@ -0,0 +1,136 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCursorMove(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	cursor := om.Cursor()
+	if cursor.Valid() {
+		t.Error("New cursor should be off the map")
+	}
+
+	var keys []string
+	for cursor.Next() {
+		keys = append(keys, cursor.Key())
+	}
+	for cursor.Prev() {
+		keys = append(keys, cursor.Key())
+	}
+	if fmt.Sprint(keys) != "[a b c c b a]" {
+		t.Error("Invalid cursor movement ", keys)
+	}
+	if cursor.Valid() || cursor.Key() != "" || cursor.Value() != 0 {
+		t.Error("Cursor should be off the map")
+	}
+
+	if !cursor.Seek("b") || cursor.Key() != "b" || cursor.Value() != 2 {
+		t.Error("Seek didn't move to the key")
+	}
+	if cursor.Seek("z") || cursor.Key() != "b" {
+		t.Error("Seek to a missing key moved the cursor")
+	}
+	if !cursor.Prev() || cursor.Key() != "a" {
+		t.Error("Prev after Seek failed")
+	}
+
+	cursor.Reset()
+	if !cursor.Prev() || cursor.Key() != "c" {
+		t.Error("Prev after Reset should move to the last element")
+	}
+
+	if New[int, int]().Cursor().Next() {
+		t.Error("Moved to an element of an empty map")
+	}
+}
+
+func TestCursorSetValue(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	cursor := om.Cursor()
+	for cursor.Next() {
+		cursor.SetValue(cursor.Value() * 10)
+	}
+	mapHasKey(t, toAny(om), "a", 10)
+	mapHasKey(t, toAny(om), "b", 20)
+
+	if err := cursor.SetValue(0); err != ErrNoCurrent {
+		t.Error("Expecting ErrNoCurrent received ", err)
+	}
+}
+
+func TestCursorDelete(t *testing.T) {
+	om := New[int, int]()
+	for k := 0; k < 6; k++ {
+		om.Set(k, k)
+	}
+
+	// Delete odd keys in a single pass
+	cursor := om.Cursor()
+	for cursor.Next() {
+		if cursor.Key()%2 == 1 {
+			if err := cursor.Delete(); err != nil {
+				t.Error(err)
+			}
+			if cursor.Valid() {
+				t.Error("Cursor still valid after Delete")
+			}
+			if err := cursor.Delete(); err != ErrNoCurrent {
+				t.Error("Deleted the same element twice")
+			}
+		}
+	}
+	if fmt.Sprint(iterKeys(om.Iter())) != "[0 2 4]" {
+		t.Error("Invalid keys after deletion ", iterKeys(om.Iter()))
+	}
+
+	// Going backwards after deleting continues from the previous element
+	cursor.Seek(2)
+	cursor.Delete()
+	if !cursor.Prev() || cursor.Key() != 0 {
+		t.Error("Prev after Delete failed")
+	}
+
+	// A key deleted through the map and inserted again is a new element
+	cursor.Seek(4)
+	om.Delete(4)
+	om.Set(4, 40)
+	if cursor.Valid() {
+		t.Error("Cursor valid on a deleted element")
+	}
+}
+
+func TestCursorInsert(t *testing.T) {
+	om := New[int, int]()
+	om.Set(10, 0)
+	om.Set(20, 0)
+
+	cursor := om.Cursor()
+	for cursor.Next() {
+		key := cursor.Key()
+		if key >= 10 {
+			cursor.InsertBefore(key-5, 0)
+			cursor.InsertAfter(key/10, 0)
+		}
+	}
+	if fmt.Sprint(iterKeys(om.Iter())) != "[5 10 1 15 20 2]" {
+		t.Error("Invalid keys after insertion ", iterKeys(om.Iter()))
+	}
+
+	cursor.Seek(10)
+	if err := cursor.InsertAfter(20, 0); err != ErrKeyExists {
+		t.Error("Expecting ErrKeyExists received ", err)
+	}
+	cursor.Reset()
+	if err := cursor.InsertBefore(100, 0); err != ErrNoCurrent {
+		t.Error("Expecting ErrNoCurrent received ", err)
+	}
+}
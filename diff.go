@@ -0,0 +1,162 @@
+package orderedmap
+
+import "sort"
+
+// Equal reports whether both maps have the same keys in the same order, with
+// values that are equal according to valueEq.
+func (om *OrderedMap[K, V]) Equal(other *OrderedMap[K, V], valueEq func(a, b V) bool) bool {
+	if om.Len() != other.Len() {
+		return false
+	}
+	if om.Len() == 0 {
+		return true
+	}
+
+	for a, b := om.root.Next, other.root.Next; a != om.root; a, b = a.Next, b.Next {
+		if a.Key != b.Key || !valueEq(a.Value, b.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualUnordered reports whether both maps have the same keys with values
+// that are equal according to valueEq, in any order.
+func (om *OrderedMap[K, V]) EqualUnordered(other *OrderedMap[K, V], valueEq func(a, b V) bool) bool {
+	if om.Len() != other.Len() {
+		return false
+	}
+
+	for key, node := range om.table {
+		otherNode, ok := other.table[key]
+		if !ok || !valueEq(node.Value, otherNode.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ChangeOp is the operation of a Change
+type ChangeOp int
+
+const (
+	// ChangeRemove deletes the key
+	ChangeRemove ChangeOp = iota
+
+	// ChangeAdd inserts a new key after the After key, or first
+	ChangeAdd
+
+	// ChangeMove moves an existing key after the After key, or first
+	ChangeMove
+
+	// ChangeUpdate replaces the key value, without moving it
+	ChangeUpdate
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeRemove:
+		return "remove"
+	case ChangeAdd:
+		return "add"
+	case ChangeMove:
+		return "move"
+	case ChangeUpdate:
+		return "update"
+	}
+	return "unknown"
+}
+
+// Change is an operation of the edit script returned by Diff
+type Change[K comparable, V any] struct {
+	Op       ChangeOp
+	Key      K
+	OldValue V    // Value before a remove or update
+	NewValue V    // Value after an add or update
+	After    K    // Key an added or moved key goes after, unless First
+	First    bool // The added or moved key goes first
+}
+
+// Diff returns the changes that turn the map into other, using valueEq to
+// compare the values of the keys in both maps. Applying the changes in order
+// to a copy of the map gives a map equal to other.
+//
+// Removals come first, then additions and moves in the other map order, and
+// lastly value updates. The moved keys are the fewest possible: the longest
+// sequence of common keys already in the right relative order stays put.
+func (om *OrderedMap[K, V]) Diff(other *OrderedMap[K, V], valueEq func(a, b V) bool) []Change[K, V] {
+	var changes []Change[K, V]
+
+	// Removed keys, and the position of the others
+	positions := make(map[K]int, om.Len())
+	for key, value := range om.All() {
+		if _, ok := other.table[key]; ok {
+			positions[key] = len(positions)
+		} else {
+			changes = append(changes, Change[K, V]{Op: ChangeRemove, Key: key, OldValue: value})
+		}
+	}
+
+	// Common keys in the other map order, and the longest subsequence of
+	// them in increasing position, which doesn't have to move.
+	common := make([]int, 0, len(positions))
+	for key := range other.Keys() {
+		if position, ok := positions[key]; ok {
+			common = append(common, position)
+		}
+	}
+	stay := longestIncreasing(common)
+
+	var updates []Change[K, V]
+	var prev K
+	first := true
+	for key, value := range other.All() {
+		position, ok := positions[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change[K, V]{Op: ChangeAdd, Key: key, NewValue: value, After: prev, First: first})
+		case !stay[position]:
+			changes = append(changes, Change[K, V]{Op: ChangeMove, Key: key, After: prev, First: first})
+		}
+
+		if ok {
+			if oldValue := om.table[key].Value; !valueEq(oldValue, value) {
+				updates = append(updates, Change[K, V]{Op: ChangeUpdate, Key: key, OldValue: oldValue, NewValue: value})
+			}
+		}
+		prev, first = key, false
+	}
+
+	return append(changes, updates...)
+}
+
+// Set of the values in the longest strictly increasing subsequence
+func longestIncreasing(values []int) map[int]bool {
+	// tails[l] is the index of the smallest value ending a subsequence of
+	// length l+1, prev links each value to the previous one in its subsequence.
+	tails := make([]int, 0, len(values))
+	prev := make([]int, len(values))
+	for i, value := range values {
+		l := sort.Search(len(tails), func(j int) bool {
+			return values[tails[j]] >= value
+		})
+		if l > 0 {
+			prev[i] = tails[l-1]
+		} else {
+			prev[i] = -1
+		}
+		if l == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[l] = i
+		}
+	}
+
+	result := make(map[int]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			result[values[i]] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func intEq(a, b int) bool {
+	return a == b
+}
+
+// Apply the changes returned by Diff
+func applyChanges[K comparable, V any](om *OrderedMap[K, V], changes []Change[K, V]) {
+	for _, change := range changes {
+		switch change.Op {
+		case ChangeRemove:
+			om.Delete(change.Key)
+		case ChangeAdd:
+			om.Set(change.Key, change.NewValue)
+			fallthrough
+		case ChangeMove:
+			if change.First {
+				om.MoveFirst(change.Key)
+			} else {
+				om.MoveAfter(change.Key, change.After)
+			}
+		case ChangeUpdate:
+			om.Set(change.Key, change.NewValue)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := NewFromPairs(Pair[string, int]{"a", 1}, Pair[string, int]{"b", 2})
+	b := NewFromPairs(Pair[string, int]{"b", 2}, Pair[string, int]{"a", 1})
+
+	if a.Equal(b, intEq) || !a.EqualUnordered(b, intEq) {
+		t.Error("Order should only matter to Equal")
+	}
+
+	b.MoveLast("b")
+	if !a.Equal(b, intEq) || !a.EqualUnordered(b, intEq) {
+		t.Error("Equal maps reported as different")
+	}
+
+	b.Set("b", 3)
+	if a.Equal(b, intEq) || a.EqualUnordered(b, intEq) {
+		t.Error("Different values reported as equal")
+	}
+
+	b.Delete("b")
+	if a.Equal(b, intEq) || a.EqualUnordered(b, intEq) {
+		t.Error("Different lengths reported as equal")
+	}
+
+	if !New[int, int]().Equal(&OrderedMap[int, int]{}, intEq) {
+		t.Error("Empty maps reported as different")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := NewFromPairs(
+		Pair[string, int]{"a", 1},
+		Pair[string, int]{"b", 2},
+		Pair[string, int]{"c", 3},
+		Pair[string, int]{"d", 4},
+	)
+	updated := NewFromPairs(
+		Pair[string, int]{"d", 4},
+		Pair[string, int]{"a", 10},
+		Pair[string, int]{"e", 5},
+		Pair[string, int]{"c", 3},
+	)
+
+	var ops []string
+	changes := old.Diff(updated, intEq)
+	for _, change := range changes {
+		ops = append(ops, fmt.Sprintf("%v %v %v %v", change.Op, change.Key, change.After, change.First))
+	}
+	expected := "[remove b  false move d  true add e a false update a  false]"
+	if fmt.Sprint(ops) != expected {
+		t.Error(fmt.Sprintf("Expecting %v received %v", expected, ops))
+	}
+
+	applyChanges(old, changes)
+	if !old.Equal(updated, intEq) {
+		t.Error("Applying the diff didn't give the same map ", old)
+	}
+
+	if changes := updated.Diff(updated.Clone(), intEq); len(changes) != 0 {
+		t.Error("Diff of equal maps isn't empty ", changes)
+	}
+}
+
+func TestDiffRandom(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		old, updated := New[int, int](), New[int, int]()
+		for _, key := range rand.Perm(20) {
+			if rand.IntN(5) != 0 {
+				old.Set(key, key)
+			}
+		}
+		for _, key := range rand.Perm(25) {
+			if rand.IntN(5) != 0 {
+				updated.Set(key, key+rand.IntN(2))
+			}
+		}
+
+		applyChanges(old, old.Diff(updated, intEq))
+		if !old.Equal(updated, intEq) {
+			t.Error(fmt.Sprintf("Expecting %v received %v", updated, old))
+		}
+	}
+
+	// Moving a single key results in a single move
+	old := New[int, int]()
+	for i := 0; i < 10; i++ {
+		old.Set(i, i)
+	}
+	updated := old.Clone()
+	updated.MoveFirst(7)
+	if changes := old.Diff(updated, intEq); len(changes) != 1 || changes[0].Key != 7 {
+		t.Error("Diff isn't minimal ", changes)
+	}
+}
@@ -0,0 +1,71 @@
+package orderedmap
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter, the keys and values are formatted with the
+// same verb, flags and width as the map, as fmt does for built-in maps. The
+// precision limits the number of elements printed instead, the rest are
+// summarized as "...(N more)" so large maps don't flood logs: %.10v prints at
+// most 10 elements. %#v prints a Go expression that builds the map.
+func (om *OrderedMap[K, V]) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		om.formatGoSyntax(f)
+		return
+	}
+	formatItems(f, verb, "OrderedMap", om.All(), om.Len())
+}
+
+// Write a call to NewFromPairs with the map pairs
+func (om *OrderedMap[K, V]) formatGoSyntax(f fmt.State) {
+	// Type arguments as printed by fmt, from the map type name
+	typeName := fmt.Sprintf("%T", om)
+	typeArgs := typeName[strings.Index(typeName, "["):]
+
+	fmt.Fprintf(f, "orderedmap.NewFromPairs%v(", typeArgs)
+	first := true
+	for key, value := range om.All() {
+		if !first {
+			fmt.Fprint(f, ", ")
+		}
+		fmt.Fprintf(f, "%#v", Pair[K, V]{Key: key, Value: value})
+		first = false
+	}
+	fmt.Fprint(f, ")")
+}
+
+// Write name[key:value key:value] formatting each key and value with the
+// directive of verb without its precision, which is the maximum number of
+// elements written.
+func formatItems[K comparable, V any](f fmt.State, verb rune, name string, items iter.Seq2[K, V], length int) {
+	directive := "%"
+	for _, flag := range "-+# 0" {
+		if f.Flag(int(flag)) {
+			directive += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive += strconv.Itoa(width)
+	}
+	directive += string(verb)
+	limit, limited := f.Precision()
+
+	fmt.Fprint(f, name, "[")
+	written := 0
+	for key, value := range items {
+		if written > 0 {
+			fmt.Fprint(f, " ")
+		}
+		if limited && written == limit {
+			fmt.Fprintf(f, "...(%d more)", length-written)
+			break
+		}
+		fmt.Fprintf(f, directive+":"+directive, key, value)
+		written++
+	}
+	fmt.Fprint(f, "]")
+}
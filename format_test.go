@@ -0,0 +1,91 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	type item struct {
+		Name string
+	}
+
+	om := New[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+
+	structs := New[int, item]()
+	structs.Set(1, item{"x"})
+
+	tests := []struct {
+		format   string
+		value    interface{}
+		expected string
+	}{
+		{"%v", om, "OrderedMap[b:2 a:1]"},
+		{"%s", New[int, int](), "OrderedMap[]"},
+		{"%q", om, `OrderedMap["b":'\x02' "a":'\x01']`},
+		{"%03d", om, "OrderedMap[%!d(string=00b):002 %!d(string=00a):001]"},
+		{"%v", structs, "OrderedMap[1:{x}]"},
+		{"%+v", structs, "OrderedMap[1:{Name:x}]"},
+		{"%#v", om, `orderedmap.NewFromPairs[string,int](orderedmap.Pair[string,int]{Key:"b", Value:2}, orderedmap.Pair[string,int]{Key:"a", Value:1})`},
+		{"%#v", New[string, int](), "orderedmap.NewFromPairs[string,int]()"},
+		{"%v", []interface{}{om}, "[OrderedMap[b:2 a:1]]"},
+	}
+	for _, test := range tests {
+		if result := fmt.Sprintf(test.format, test.value); result != test.expected {
+			t.Error(fmt.Sprintf("%v: Expecting %v received %v", test.format, test.expected, result))
+		}
+	}
+
+	if om.String() != "OrderedMap[b:2 a:1]" {
+		t.Error("Invalid String result ", om.String())
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	om := newIntMap(1000)
+	if result := fmt.Sprintf("%.2v", om); result != "OrderedMap[0:0 1:1 ...(998 more)]" {
+		t.Error("Invalid truncated map ", result)
+	}
+	if result := fmt.Sprintf("%.*v", 0, om); result != "OrderedMap[...(1000 more)]" {
+		t.Error("Invalid map truncated to 0 elements ", result)
+	}
+
+	// The precision isn't applied to the elements, and doesn't change String
+	words := NewFromPairs(Pair[string, float64]{"alpha", 1.5}, Pair[string, float64]{"beta", 2})
+	if result := fmt.Sprintf("%6.1v", words); result != "OrderedMap[ alpha:   1.5 ...(1 more)]" {
+		t.Error("Invalid truncated map with width ", result)
+	}
+	if result := words.String(); result != "OrderedMap[alpha:1.5 beta:2]" {
+		t.Error("Invalid String after formatting ", result)
+	}
+
+	// The limit is only reached with more elements
+	small := newIntMap(2)
+	if result := fmt.Sprintf("%.2v", small); result != "OrderedMap[0:0 1:1]" {
+		t.Error("Invalid map at the limit ", result)
+	}
+
+	sm := NewSync[int, int]()
+	sm.Set(1, 1)
+	sm.Set(2, 2)
+	if result := fmt.Sprintf("%.1v", sm); result != "OrderedMap[1:1 ...(1 more)]" {
+		t.Error("Invalid SyncOrderedMap format ", result)
+	}
+	sorted := NewSortedMap[int, int]()
+	sorted.Set(1, 1)
+	sorted.Set(2, 2)
+	if result := fmt.Sprintf("%.1v", sorted); result != "SortedMap[1:1 ...(1 more)]" {
+		t.Error("Invalid SortedMap format ", result)
+	}
+}
+
+func TestSortedMapFormat(t *testing.T) {
+	sm := NewSortedMap[string, int]()
+	sm.Set("b", 2)
+	sm.Set("a", 1)
+	if result := fmt.Sprintf("%v %q", sm, sm); result != `SortedMap[a:1 b:2] SortedMap["a":'\x01' "b":'\x02']` {
+		t.Error("Invalid SortedMap format ", result)
+	}
+}
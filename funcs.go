@@ -0,0 +1,106 @@
+package orderedmap
+
+// Filter returns a new map with the key:value pairs for which pred returns
+// true, in the same order.
+func (om *OrderedMap[K, V]) Filter(pred func(key K, value V) bool) *OrderedMap[K, V] {
+	filtered := New[K, V]()
+	for key, value := range om.All() {
+		if pred(key, value) {
+			filtered.insert(key, value, filtered.root)
+		}
+	}
+	return filtered
+}
+
+// Partition splits the map in two new maps, one with the key:value pairs for
+// which pred returns true and another with the rest, both in the same order.
+func (om *OrderedMap[K, V]) Partition(pred func(key K, value V) bool) (matched *OrderedMap[K, V], rest *OrderedMap[K, V]) {
+	matched, rest = New[K, V](), New[K, V]()
+	for key, value := range om.All() {
+		if pred(key, value) {
+			matched.insert(key, value, matched.root)
+		} else {
+			rest.insert(key, value, rest.root)
+		}
+	}
+	return
+}
+
+// DeleteFunc deletes the key:value pairs for which pred returns true, and
+// returns how many were deleted. pred must not modify the map.
+//
+// Deleting while iterating is safe: iterators and cursors positioned on a
+// deleted element continue from the closest element that wasn't deleted.
+func (om *OrderedMap[K, V]) DeleteFunc(pred func(key K, value V) bool) (deleted int) {
+	if om.Len() == 0 {
+		return 0
+	}
+	defer om.group()()
+
+	// Consecutive deleted nodes, their pointers are updated to skip the whole
+	// run once the next remaining node is found.
+	var run []*node[K, V]
+	for n := om.root.Next; n != om.root; n = n.Next {
+		if pred(n.Key, n.Value) {
+			om.remove(n, EventDelete)
+			run = append(run, n)
+			deleted++
+			continue
+		}
+		skipDeleted(run, n)
+		run = run[:0]
+	}
+	skipDeleted(run, om.root)
+	return deleted
+}
+
+// RetainFunc deletes the key:value pairs for which pred returns false, with
+// the same guarantees as DeleteFunc, and returns how many were deleted.
+func (om *OrderedMap[K, V]) RetainFunc(pred func(key K, value V) bool) (deleted int) {
+	return om.DeleteFunc(func(key K, value V) bool {
+		return !pred(key, value)
+	})
+}
+
+// Point a run of deleted nodes to the remaining nodes around it
+func skipDeleted[K comparable, V any](run []*node[K, V], next *node[K, V]) {
+	for _, n := range run {
+		n.Next, n.Prev = next, next.Prev
+	}
+}
+
+// MapValues returns a new map with the same keys and order, and the values
+// returned by fn.
+func MapValues[K comparable, V any, W any](om *OrderedMap[K, V], fn func(key K, value V) W) *OrderedMap[K, W] {
+	mapped := NewWithCapacity[K, W](om.Len())
+	for key, value := range om.All() {
+		mapped.insert(key, fn(key, value), mapped.root)
+	}
+	return mapped
+}
+
+// Reduce calls fn with the accumulated result and each key:value pair in
+// order, starting with initial, and returns the final result.
+func Reduce[K comparable, V any, A any](om *OrderedMap[K, V], initial A, fn func(acc A, key K, value V) A) A {
+	acc := initial
+	for key, value := range om.All() {
+		acc = fn(acc, key, value)
+	}
+	return acc
+}
+
+// GroupBy splits the map in groups by the result of fn. Groups are ordered
+// by the first key in them, and each one keeps the order of its keys.
+func GroupBy[K comparable, V any, G comparable](om *OrderedMap[K, V], fn func(key K, value V) G) *OrderedMap[G, *OrderedMap[K, V]] {
+	groups := New[G, *OrderedMap[K, V]]()
+	for key, value := range om.All() {
+		name := fn(key, value)
+		group, ok := groups.Get(name)
+		if !ok {
+			group = New[K, V]()
+			groups.insert(name, group, groups.root)
+		}
+		group.insert(key, value, group.root)
+	}
+	return groups
+}
@@ -0,0 +1,121 @@
+package orderedmap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newIntMap(n int) *OrderedMap[int, int] {
+	om := New[int, int]()
+	for i := 0; i < n; i++ {
+		om.Set(i, i*i)
+	}
+	return om
+}
+
+func isEven(key int, value int) bool {
+	return key%2 == 0
+}
+
+func TestFilterPartition(t *testing.T) {
+	om := newIntMap(6)
+
+	even := om.Filter(isEven)
+	if fmt.Sprint(iterKeys(even.Iter())) != "[0 2 4]" || om.Len() != 6 {
+		t.Error("Invalid Filter result ", even)
+	}
+
+	matched, rest := om.Partition(isEven)
+	if fmt.Sprint(iterKeys(matched.Iter())) != "[0 2 4]" || fmt.Sprint(iterKeys(rest.Iter())) != "[1 3 5]" {
+		t.Error("Invalid Partition result ", matched, rest)
+	}
+	if value, _ := rest.Get(5); value != 25 {
+		t.Error("Partition changed the values")
+	}
+}
+
+func TestMapValuesReduce(t *testing.T) {
+	om := newIntMap(4)
+
+	labels := MapValues(om, func(key int, value int) string {
+		return fmt.Sprintf("%v^2=%v", key, value)
+	})
+	if value, _ := labels.Get(3); value != "3^2=9" || labels.Len() != 4 {
+		t.Error("Invalid MapValues result ", labels)
+	}
+
+	joined := Reduce(labels, "", func(acc string, key int, value string) string {
+		return acc + value + ";"
+	})
+	if joined != "0^2=0;1^2=1;2^2=4;3^2=9;" {
+		t.Error("Invalid Reduce result ", joined)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	om := New[string, int]()
+	for i, name := range []string{"bob", "alice", "bill", "anna", "carl"} {
+		om.Set(name, i)
+	}
+
+	groups := GroupBy(om, func(key string, value int) string {
+		return key[:1]
+	})
+
+	var result []string
+	for initial, group := range groups.All() {
+		result = append(result, initial+":"+strings.Join(iterKeys(group.Iter()), ","))
+	}
+	if fmt.Sprint(result) != "[b:bob,bill a:alice,anna c:carl]" {
+		t.Error("Invalid groups ", result)
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	om := newIntMap(10)
+	om.EnableIndex()
+
+	if deleted := om.DeleteFunc(isEven); deleted != 5 {
+		t.Error("Expecting 5 deleted, received ", deleted)
+	}
+	if deleted := om.RetainFunc(func(key int, value int) bool { return key < 7 }); deleted != 2 {
+		t.Error("Expecting 2 deleted, received ", deleted)
+	}
+	if fmt.Sprint(iterKeys(om.Iter())) != "[1 3 5]" {
+		t.Error("Invalid keys after DeleteFunc ", om)
+	}
+	if index, _ := om.IndexOf(5); index != 2 {
+		t.Error("Index not updated ", index)
+	}
+}
+
+func TestDeleteFuncWhileIterating(t *testing.T) {
+	// Delete runs of keys, including the iterator current one, from an
+	// iterator in each direction.
+	for _, reverse := range []bool{false, true} {
+		om := newIntMap(10)
+		iter := om.Iter()
+		if reverse {
+			iter = om.IterReverse()
+		}
+
+		var keys []int
+		for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+			keys = append(keys, key)
+			if key == 5 {
+				om.DeleteFunc(func(key int, value int) bool {
+					return (key >= 2 && key <= 6) || key == 9 || key == 0
+				})
+			}
+		}
+
+		expected := "[0 1 2 3 4 5 7 8]"
+		if reverse {
+			expected = "[9 8 7 6 5 1]"
+		}
+		if fmt.Sprint(keys) != expected {
+			t.Error(fmt.Sprintf("Expecting %v received %v", expected, keys))
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package orderedmap
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// A hash array mapped trie, the persistent hash table used by PersistentMap.
+// Each level consumes 5 bits of the key hash to select one of 32 slots, only
+// the used slots are stored, compressed with a bitmap. Updates copy the path
+// from the root to the changed slot, unless the nodes belong to the transient
+// making the change, then they are modified in place.
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// Identifies the nodes created by a transient, which it can modify in place.
+// It isn't empty because pointers to zero size values may not be unique.
+type editToken struct {
+	_ byte
+}
+
+type hamtNode[K comparable, V any] struct {
+	bitmap uint32
+	slots  []hamtSlot[K, V]
+	owner  *editToken
+}
+
+// A slot holds either a child node or the entries with the same key hash,
+// usually one but more when hashes collide.
+type hamtSlot[K comparable, V any] struct {
+	child   *hamtNode[K, V]
+	hash    uint64
+	entries []hamtEntry[K, V]
+}
+
+// A key, its value and its sequence number in the order tree
+type hamtEntry[K comparable, V any] struct {
+	key   K
+	value V
+	seq   int64
+}
+
+// Bit of a hash in a node bitmap at shift
+func hamtBit(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & hamtMask)
+}
+
+// Position in a node slots of the slot selected by bit
+func (n *hamtNode[K, V]) position(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Return a node that can be modified by owner, n itself if owner created it
+// or otherwise a copy.
+func (n *hamtNode[K, V]) edit(owner *editToken) *hamtNode[K, V] {
+	if owner != nil && n.owner == owner {
+		return n
+	}
+	return &hamtNode[K, V]{
+		bitmap: n.bitmap,
+		slots:  slices.Clone(n.slots),
+		owner:  owner,
+	}
+}
+
+// Find the entry for a key
+func (n *hamtNode[K, V]) get(hash uint64, key K) (entry hamtEntry[K, V], ok bool) {
+	for shift := uint(0); n != nil; shift += hamtBits {
+		bit := hamtBit(hash, shift)
+		if n.bitmap&bit == 0 {
+			return
+		}
+
+		slot := &n.slots[n.position(bit)]
+		if slot.child == nil {
+			if slot.hash == hash {
+				for _, e := range slot.entries {
+					if e.key == key {
+						return e, true
+					}
+				}
+			}
+			return
+		}
+		n = slot.child
+	}
+	return
+}
+
+// Set the entry for its key, returning the updated node and whether the key
+// is new. n can be nil to create a node.
+func hamtSet[K comparable, V any](n *hamtNode[K, V], hash uint64, entry hamtEntry[K, V], shift uint, owner *editToken) (*hamtNode[K, V], bool) {
+	if n == nil {
+		n = &hamtNode[K, V]{owner: owner}
+	}
+	bit := hamtBit(hash, shift)
+	position := n.position(bit)
+
+	if n.bitmap&bit == 0 {
+		n = n.edit(owner)
+		n.slots = slices.Insert(n.slots, position, hamtSlot[K, V]{
+			hash:    hash,
+			entries: []hamtEntry[K, V]{entry},
+		})
+		n.bitmap |= bit
+		return n, true
+	}
+
+	slot := n.slots[position]
+	added := false
+	switch {
+	case slot.child != nil:
+		slot.child, added = hamtSet(slot.child, hash, entry, shift+hamtBits, owner)
+
+	case slot.hash == hash:
+		// Entries are shared between versions, so they are always copied
+		i := slices.IndexFunc(slot.entries, func(e hamtEntry[K, V]) bool {
+			return e.key == entry.key
+		})
+		if i < 0 {
+			slot.entries = append(slices.Clip(slot.entries), entry)
+			added = true
+		} else {
+			slot.entries = slices.Clone(slot.entries)
+			slot.entries[i] = entry
+		}
+
+	default:
+		// Different hash, both go in a new child node
+		child := &hamtNode[K, V]{
+			bitmap: hamtBit(slot.hash, shift+hamtBits),
+			slots:  []hamtSlot[K, V]{slot},
+			owner:  owner,
+		}
+		slot = hamtSlot[K, V]{}
+		slot.child, added = hamtSet(child, hash, entry, shift+hamtBits, owner)
+	}
+
+	n = n.edit(owner)
+	n.slots[position] = slot
+	return n, added
+}
+
+// Delete the entry for a key, returning the updated node, nil if it is left
+// empty, and whether the key was found.
+func hamtDelete[K comparable, V any](n *hamtNode[K, V], hash uint64, key K, shift uint, owner *editToken) (*hamtNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	bit := hamtBit(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	position := n.position(bit)
+	slot := n.slots[position]
+
+	remove := false
+	if slot.child != nil {
+		child, ok := hamtDelete(slot.child, hash, key, shift+hamtBits, owner)
+		if !ok {
+			return n, false
+		}
+		switch {
+		case child == nil:
+			remove = true
+		case len(child.slots) == 1 && child.slots[0].child == nil:
+			// A single leaf doesn't need its own node
+			slot = child.slots[0]
+		default:
+			slot.child = child
+		}
+	} else {
+		if slot.hash != hash {
+			return n, false
+		}
+		i := slices.IndexFunc(slot.entries, func(e hamtEntry[K, V]) bool {
+			return e.key == key
+		})
+		if i < 0 {
+			return n, false
+		}
+		if len(slot.entries) == 1 {
+			remove = true
+		} else {
+			slot.entries = slices.Delete(slices.Clone(slot.entries), i, i+1)
+		}
+	}
+
+	n = n.edit(owner)
+	if remove {
+		n.slots = slices.Delete(n.slots, position, position+1)
+		n.bitmap &^= bit
+		if len(n.slots) == 0 {
+			return nil, true
+		}
+	} else {
+		n.slots[position] = slot
+	}
+	return n, true
+}
@@ -0,0 +1,260 @@
+package orderedmap
+
+import "math/rand/v2"
+
+// rankIndex is an order-statistic tree over the map linked list, used to
+// find the position of a node and the node at a position in O(log n). It is
+// a treap keyed implicitly by list position: the in-order traversal of the
+// tree visits the nodes in list order, and each tree node stores the size of
+// its subtree.
+type rankIndex[K comparable, V any] struct {
+	root *rankNode[K, V]
+}
+
+type rankNode[K comparable, V any] struct {
+	left     *rankNode[K, V]
+	right    *rankNode[K, V]
+	parent   *rankNode[K, V]
+	size     int
+	priority uint32
+	elem     *node[K, V]
+}
+
+func (rn *rankNode[K, V]) getSize() int {
+	if rn == nil {
+		return 0
+	}
+	return rn.size
+}
+
+func (rn *rankNode[K, V]) updateSize() {
+	rn.size = 1 + rn.left.getSize() + rn.right.getSize()
+}
+
+// Build an index for the nodes of a list, in order
+func newRankIndex[K comparable, V any](root *node[K, V]) *rankIndex[K, V] {
+	index := &rankIndex[K, V]{}
+	for n := root.Next; n != root; n = n.Next {
+		index.insertBefore(n, root)
+	}
+	return index
+}
+
+// Insert a list node just before mark, which can be the list sentinel
+func (ri *rankIndex[K, V]) insertBefore(elem *node[K, V], mark *node[K, V]) {
+	rn := &rankNode[K, V]{size: 1, priority: rand.Uint32(), elem: elem}
+	elem.rank = rn
+
+	// The new node goes just before mark in the in-order traversal, either
+	// as mark left child or as the rightmost node of its left subtree.
+	var parent *rankNode[K, V]
+	if mark.rank == nil {
+		// Sentinel, append after the last node
+		for parent = ri.root; parent != nil && parent.right != nil; {
+			parent = parent.right
+		}
+		if parent == nil {
+			ri.root = rn
+			return
+		}
+		parent.right = rn
+	} else if parent = mark.rank; parent.left == nil {
+		parent.left = rn
+	} else {
+		for parent = parent.left; parent.right != nil; {
+			parent = parent.right
+		}
+		parent.right = rn
+	}
+	rn.parent = parent
+
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
+
+	// Restore the heap order of priorities
+	for rn.parent != nil && rn.priority < rn.parent.priority {
+		ri.rotateUp(rn)
+	}
+}
+
+// Remove a list node from the index
+func (ri *rankIndex[K, V]) remove(elem *node[K, V]) {
+	rn := elem.rank
+	elem.rank = nil
+
+	// Rotate down until it is a leaf
+	for rn.left != nil || rn.right != nil {
+		child := rn.left
+		if child == nil || (rn.right != nil && rn.right.priority < child.priority) {
+			child = rn.right
+		}
+		ri.rotateUp(child)
+	}
+
+	parent := rn.parent
+	switch {
+	case parent == nil:
+		ri.root = nil
+	case parent.left == rn:
+		parent.left = nil
+	default:
+		parent.right = nil
+	}
+	for p := parent; p != nil; p = p.parent {
+		p.size--
+	}
+}
+
+// Rotate a node above its parent, keeping the in-order traversal
+func (ri *rankIndex[K, V]) rotateUp(rn *rankNode[K, V]) {
+	parent := rn.parent
+	grandparent := parent.parent
+
+	if parent.left == rn {
+		parent.left = rn.right
+		if rn.right != nil {
+			rn.right.parent = parent
+		}
+		rn.right = parent
+	} else {
+		parent.right = rn.left
+		if rn.left != nil {
+			rn.left.parent = parent
+		}
+		rn.left = parent
+	}
+	parent.parent = rn
+	rn.parent = grandparent
+
+	switch {
+	case grandparent == nil:
+		ri.root = rn
+	case grandparent.left == parent:
+		grandparent.left = rn
+	default:
+		grandparent.right = rn
+	}
+
+	parent.updateSize()
+	rn.updateSize()
+}
+
+// Position of a list node
+func (ri *rankIndex[K, V]) rankOf(elem *node[K, V]) int {
+	rn := elem.rank
+	position := rn.left.getSize()
+	for ; rn.parent != nil; rn = rn.parent {
+		if rn.parent.right == rn {
+			position += rn.parent.left.getSize() + 1
+		}
+	}
+	return position
+}
+
+// List node at a valid position
+func (ri *rankIndex[K, V]) at(position int) *node[K, V] {
+	rn := ri.root
+	for {
+		leftSize := rn.left.getSize()
+		switch {
+		case position < leftSize:
+			rn = rn.left
+		case position == leftSize:
+			return rn.elem
+		default:
+			position -= leftSize + 1
+			rn = rn.right
+		}
+	}
+}
+
+// EnableIndex builds a position index for the map, so GetAt, IndexOf and
+// DeleteAt run in O(log n) instead of O(n). Keeping the index up to date
+// makes insertions, deletions and moves O(log n) too, so it is only worth
+// enabling for maps that use positional access on many keys.
+func (om *OrderedMap[K, V]) EnableIndex() {
+	if om.index == nil {
+		om.index = newRankIndex(om.root)
+	}
+}
+
+// DisableIndex drops the position index built by EnableIndex
+func (om *OrderedMap[K, V]) DisableIndex() {
+	if om.index == nil {
+		return
+	}
+	for n := om.root.Next; n != om.root; n = n.Next {
+		n.rank = nil
+	}
+	om.index = nil
+}
+
+// Convert a position that can be negative, counting from the end, to an
+// index from the start. ok is false if it is out of range.
+func (om *OrderedMap[K, V]) position(i int) (position int, ok bool) {
+	if i < 0 {
+		i += om.Len()
+	}
+	if i < 0 || i >= om.Len() {
+		return 0, false
+	}
+	return i, true
+}
+
+// Node at a valid position
+func (om *OrderedMap[K, V]) nodeAt(position int) *node[K, V] {
+	if om.index != nil {
+		return om.index.at(position)
+	}
+
+	// Walk from the closest end
+	if position < om.Len()/2 {
+		n := om.root.Next
+		for ; position > 0; position-- {
+			n = n.Next
+		}
+		return n
+	}
+	n := om.root.Prev
+	for position = om.Len() - 1 - position; position > 0; position-- {
+		n = n.Prev
+	}
+	return n
+}
+
+// GetAt returns the key and value at position i in the map order, negative
+// positions count from the end so -1 is the last element.
+func (om *OrderedMap[K, V]) GetAt(i int) (key K, value V, ok bool) {
+	position, ok := om.position(i)
+	if !ok {
+		return
+	}
+	n := om.nodeAt(position)
+	return n.Key, n.Value, true
+}
+
+// IndexOf returns the position of a key in the map order
+func (om *OrderedMap[K, V]) IndexOf(key K) (index int, ok bool) {
+	n, ok := om.table[key]
+	if !ok {
+		return -1, false
+	}
+	if om.index != nil {
+		return om.index.rankOf(n), true
+	}
+
+	for c := om.root.Next; c != n; c = c.Next {
+		index++
+	}
+	return index, true
+}
+
+// DeleteAt removes and returns the key:value pair at position i, negative
+// positions count from the end.
+func (om *OrderedMap[K, V]) DeleteAt(i int) (key K, value V, ok bool) {
+	if key, value, ok = om.GetAt(i); ok {
+		om.Delete(key)
+	}
+	return
+}
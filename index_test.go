@@ -0,0 +1,196 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestGetAt(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		om := New[string, int]()
+		if indexed {
+			om.EnableIndex()
+		}
+		om.Set("a", 0)
+		om.Set("b", 1)
+		om.Set("c", 2)
+
+		tests := []struct {
+			index int
+			key   string
+			ok    bool
+		}{
+			{0, "a", true},
+			{2, "c", true},
+			{-1, "c", true},
+			{-3, "a", true},
+			{3, "", false},
+			{-4, "", false},
+		}
+		for _, test := range tests {
+			if key, _, ok := om.GetAt(test.index); key != test.key || ok != test.ok {
+				t.Error(fmt.Sprintf("GetAt(%v) expecting %v %v received %v %v",
+					test.index, test.key, test.ok, key, ok))
+			}
+		}
+
+		if _, _, ok := New[string, int]().GetAt(0); ok {
+			t.Error("GetAt returned an element from an empty map")
+		}
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		om := New[string, int]()
+		if indexed {
+			om.EnableIndex()
+		}
+		om.Set("a", 0)
+		om.Set("b", 1)
+		om.Set("c", 2)
+		om.MoveFirst("c")
+
+		if index, ok := om.IndexOf("c"); index != 0 || !ok {
+			t.Error(fmt.Sprintf("Expecting 0, true -> Returned %v %v", index, ok))
+		}
+		if index, ok := om.IndexOf("b"); index != 2 || !ok {
+			t.Error(fmt.Sprintf("Expecting 2, true -> Returned %v %v", index, ok))
+		}
+		if index, ok := om.IndexOf("z"); index != -1 || ok {
+			t.Error(fmt.Sprintf("Expecting -1, false -> Returned %v %v", index, ok))
+		}
+	}
+}
+
+func TestDeleteAt(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		om := New[string, int]()
+		if indexed {
+			om.EnableIndex()
+		}
+		om.Set("a", 0)
+		om.Set("b", 1)
+		om.Set("c", 2)
+
+		if key, value, ok := om.DeleteAt(1); key != "b" || value != 1 || !ok {
+			t.Error(fmt.Sprintf("Expecting b, 1, true -> Returned %v %v %v", key, value, ok))
+		}
+		if key, _, ok := om.DeleteAt(-1); key != "c" || !ok {
+			t.Error(fmt.Sprintf("Expecting c, true -> Returned %v %v", key, ok))
+		}
+		if _, _, ok := om.DeleteAt(1); ok {
+			t.Error("Deleted an out of range position")
+		}
+		if key, _, _ := om.GetAt(0); key != "a" || om.Len() != 1 {
+			t.Error("DeleteAt removed the wrong keys")
+		}
+	}
+}
+
+// Compare positional access against a slice while applying random operations
+func TestIndexRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	om := New[int, int]()
+	om.EnableIndex()
+	var model []int
+
+	remove := func(key int) {
+		for i, k := range model {
+			if k == key {
+				model = append(model[:i], model[i+1:]...)
+				return
+			}
+		}
+	}
+
+	for step := 0; step < 5000; step++ {
+		key := rnd.Intn(200)
+		switch op := rnd.Intn(6); {
+		case op < 2:
+			if _, ok := om.Get(key); !ok {
+				model = append(model, key)
+			}
+			om.Set(key, key)
+		case op == 2:
+			om.Delete(key)
+			remove(key)
+		case op == 3:
+			if om.MoveFirst(key) {
+				remove(key)
+				model = append([]int{key}, model...)
+			}
+		case op == 4:
+			if om.MoveLast(key) {
+				remove(key)
+				model = append(model, key)
+			}
+		default:
+			if len(model) > 0 {
+				i := rnd.Intn(len(model))
+				deleted, _, _ := om.DeleteAt(i)
+				if deleted != model[i] {
+					t.Fatal(fmt.Sprintf("DeleteAt(%v) expecting %v received %v", i, model[i], deleted))
+				}
+				model = append(model[:i], model[i+1:]...)
+			}
+		}
+
+		if om.Len() != len(model) {
+			t.Fatal("Length mismatch at step ", step)
+		}
+		if step%50 != 0 {
+			continue
+		}
+		for i, expected := range model {
+			if key, _, _ := om.GetAt(i); key != expected {
+				t.Fatal(fmt.Sprintf("Step %v: GetAt(%v) expecting %v received %v", step, i, expected, key))
+			}
+			if index, _ := om.IndexOf(expected); index != i {
+				t.Fatal(fmt.Sprintf("Step %v: IndexOf(%v) expecting %v received %v", step, expected, i, index))
+			}
+		}
+	}
+
+	// Disabling the index keeps the same results
+	om.DisableIndex()
+	for i, expected := range model {
+		if key, _, _ := om.GetAt(i); key != expected {
+			t.Fatal(fmt.Sprintf("GetAt(%v) expecting %v received %v", i, expected, key))
+		}
+	}
+}
+
+// Iterators keep working when deleting the current key of an indexed map
+func TestIndexIterDelete(t *testing.T) {
+	om := New[int, int]()
+	om.EnableIndex()
+	for k := 0; k < 10; k++ {
+		om.Set(k, k)
+	}
+
+	for key := range om.Keys() {
+		if key%2 == 0 {
+			om.Delete(key)
+		}
+	}
+	for i := 0; i < om.Len(); i++ {
+		if key, _, _ := om.GetAt(i); key != 2*i+1 {
+			t.Error(fmt.Sprintf("GetAt(%v) expecting %v received %v", i, 2*i+1, key))
+		}
+	}
+}
+
+func BenchmarkGetAtIndexed(b *testing.B) {
+	om := New[int, int]()
+	om.EnableIndex()
+	for k := 0; k < 100000; k++ {
+		om.Set(k, k)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		om.GetAt(i % 100000)
+	}
+}
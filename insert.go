@@ -0,0 +1,87 @@
+package orderedmap
+
+import "errors"
+
+// Errors returned by the operations relative to another key
+var (
+	ErrKeyNotFound  = errors.New("orderedmap: key not found")
+	ErrKeyExists    = errors.New("orderedmap: key already exists")
+	ErrMarkNotFound = errors.New("orderedmap: mark key not found")
+	ErrSameKey      = errors.New("orderedmap: key and mark are the same")
+)
+
+// InsertBefore inserts a new key:value pair just before the mark key. The map
+// is left unchanged and an error returned if mark is not in the map, if key
+// is the mark or if it already exists.
+func (om *OrderedMap[K, V]) InsertBefore(mark K, key K, value V) error {
+	markNode, err := om.insertMark(mark, key)
+	if err != nil {
+		return err
+	}
+	om.insert(key, value, markNode)
+	return nil
+}
+
+// InsertAfter inserts a new key:value pair just after the mark key, with the
+// same errors as InsertBefore.
+func (om *OrderedMap[K, V]) InsertAfter(mark K, key K, value V) error {
+	markNode, err := om.insertMark(mark, key)
+	if err != nil {
+		return err
+	}
+	om.insert(key, value, markNode.Next)
+	return nil
+}
+
+// MoveBefore moves an existing key just before the mark key. The map is left
+// unchanged and an error returned if either key is not in the map or they
+// are the same key.
+func (om *OrderedMap[K, V]) MoveBefore(key K, mark K) error {
+	moved, markNode, err := om.moveMark(key, mark)
+	if err != nil {
+		return err
+	}
+	om.move(moved, markNode)
+	return nil
+}
+
+// MoveAfter moves an existing key just after the mark key, with the same
+// errors as MoveBefore.
+func (om *OrderedMap[K, V]) MoveAfter(key K, mark K) error {
+	moved, markNode, err := om.moveMark(key, mark)
+	if err != nil {
+		return err
+	}
+	om.move(moved, markNode.Next)
+	return nil
+}
+
+// Check the arguments of an insertion and return the mark node
+func (om *OrderedMap[K, V]) insertMark(mark K, key K) (*node[K, V], error) {
+	markNode, ok := om.table[mark]
+	switch {
+	case !ok:
+		return nil, ErrMarkNotFound
+	case key == mark:
+		return nil, ErrSameKey
+	}
+	if _, ok := om.table[key]; ok {
+		return nil, ErrKeyExists
+	}
+	return markNode, nil
+}
+
+// Check the arguments of a move and return the moved and mark nodes
+func (om *OrderedMap[K, V]) moveMark(key K, mark K) (moved *node[K, V], markNode *node[K, V], err error) {
+	moved, ok := om.table[key]
+	if !ok {
+		return nil, nil, ErrKeyNotFound
+	}
+	if markNode, ok = om.table[mark]; !ok {
+		return nil, nil, ErrMarkNotFound
+	}
+	if moved == markNode {
+		return nil, nil, ErrSameKey
+	}
+	return moved, markNode, nil
+}
@@ -0,0 +1,138 @@
+package orderedmap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Map keys joined in order, checking the reverse links match
+func joinKeys(om *OrderedMap[string, int]) string {
+	keys, reversed := iterKeys(om.Iter()), iterKeys(om.IterReverse())
+	slices.Reverse(reversed)
+	if !slices.Equal(keys, reversed) {
+		return "broken list"
+	}
+	return strings.Join(keys, "")
+}
+
+func TestInsertBefore(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("c", 3)
+
+	if err := om.InsertBefore("c", "b", 2); err != nil {
+		t.Error(err)
+	}
+	if err := om.InsertBefore("a", "_", 0); err != nil {
+		t.Error(err)
+	}
+	if keys := joinKeys(om); keys != "_abc" {
+		t.Error("Expecting _abc received ", keys)
+	}
+	mapHasKey(t, toAny(om), "b", 2)
+
+	if err := om.InsertBefore("z", "y", 0); err != ErrMarkNotFound {
+		t.Error("Expecting ErrMarkNotFound received ", err)
+	}
+	if err := om.InsertBefore("a", "a", 0); err != ErrSameKey {
+		t.Error("Expecting ErrSameKey received ", err)
+	}
+	if err := om.InsertBefore("a", "c", 0); err != ErrKeyExists {
+		t.Error("Expecting ErrKeyExists received ", err)
+	}
+	if keys := joinKeys(om); keys != "_abc" || om.Len() != 4 {
+		t.Error("Failed insertion modified the map ", keys)
+	}
+	mapHasKey(t, toAny(om), "c", 3)
+}
+
+func TestInsertAfter(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("c", 3)
+
+	if err := om.InsertAfter("a", "b", 2); err != nil {
+		t.Error(err)
+	}
+	if err := om.InsertAfter("c", "d", 4); err != nil {
+		t.Error(err)
+	}
+	if keys := joinKeys(om); keys != "abcd" {
+		t.Error("Expecting abcd received ", keys)
+	}
+	if key, _, _ := om.GetLast(); key != "d" {
+		t.Error("Inserting after the last key didn't update the end")
+	}
+
+	if err := om.InsertAfter("z", "y", 0); err != ErrMarkNotFound {
+		t.Error("Expecting ErrMarkNotFound received ", err)
+	}
+	if err := om.InsertAfter("b", "b", 0); err != ErrSameKey {
+		t.Error("Expecting ErrSameKey received ", err)
+	}
+	if err := om.InsertAfter("b", "a", 0); err != ErrKeyExists {
+		t.Error("Expecting ErrKeyExists received ", err)
+	}
+}
+
+func TestMoveBeforeAfter(t *testing.T) {
+	om := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d"} {
+		om.Set(key, i)
+	}
+
+	tests := []struct {
+		move   func() error
+		result string
+	}{
+		{func() error { return om.MoveBefore("d", "a") }, "dabc"},
+		{func() error { return om.MoveAfter("d", "c") }, "abcd"},
+		{func() error { return om.MoveBefore("a", "b") }, "abcd"},
+		{func() error { return om.MoveAfter("b", "a") }, "abcd"},
+		{func() error { return om.MoveAfter("a", "b") }, "bacd"},
+		{func() error { return om.MoveBefore("d", "c") }, "badc"},
+	}
+	for _, test := range tests {
+		if err := test.move(); err != nil {
+			t.Error(err)
+		}
+		if keys := joinKeys(om); keys != test.result {
+			t.Error(fmt.Sprintf("Expecting %v received %v", test.result, keys))
+		}
+	}
+
+	if err := om.MoveBefore("z", "a"); err != ErrKeyNotFound {
+		t.Error("Expecting ErrKeyNotFound received ", err)
+	}
+	if err := om.MoveAfter("a", "z"); err != ErrMarkNotFound {
+		t.Error("Expecting ErrMarkNotFound received ", err)
+	}
+	if err := om.MoveAfter("a", "a"); err != ErrSameKey {
+		t.Error("Expecting ErrSameKey received ", err)
+	}
+	if keys := joinKeys(om); keys != "badc" {
+		t.Error("Failed move modified the map ", keys)
+	}
+}
+
+func TestInsertIndexed(t *testing.T) {
+	om := New[string, int]()
+	om.EnableIndex()
+	om.Set("a", 0)
+	om.Set("d", 0)
+	om.InsertAfter("a", "b", 0)
+	om.InsertBefore("d", "c", 0)
+	om.MoveBefore("d", "a")
+	om.MoveAfter("a", "c")
+
+	for i, expected := range []string{"d", "b", "c", "a"} {
+		if key, _, _ := om.GetAt(i); key != expected {
+			t.Error(fmt.Sprintf("GetAt(%v) expecting %v received %v", i, expected, key))
+		}
+		if index, _ := om.IndexOf(expected); index != i {
+			t.Error(fmt.Sprintf("IndexOf(%v) expecting %v received %v", expected, i, index))
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package orderedmap
+
+import "errors"
+
+// ErrTxDone is returned when committing or rolling back a transaction that
+// already ended.
+var ErrTxDone = errors.New("orderedmap: transaction already committed or rolled back")
+
+// The change journal records every change to a map while a transaction is
+// open or the undo history is enabled, with enough information to revert it
+// and apply it again. Positions are recorded as the key of the next element,
+// which is in the map again when the later changes have been reverted.
+type journal[K comparable, V any] struct {
+	entries   []journalEntry[K, V] // Changes in the open transactions
+	txs       []*Tx[K, V]          // Open transactions, outermost first
+	history   bool
+	limit     int
+	undo      [][]journalEntry[K, V]
+	redo      [][]journalEntry[K, V]
+	replaying bool
+}
+
+type journalEntry[K comparable, V any] struct {
+	op       EventType
+	key      K
+	oldValue V
+	newValue V
+
+	// Next element before and after the change, nil for the end
+	oldNext *K
+	newNext *K
+
+	// Contents before and after a reset
+	before []Pair[K, V]
+	after  []Pair[K, V]
+}
+
+// Tx is a transaction started with Begin. Every change to the map while it
+// is open is recorded, so Rollback can restore the values and order the map
+// had when it began.
+type Tx[K comparable, V any] struct {
+	om    *OrderedMap[K, V]
+	start int
+	done  bool
+}
+
+// Begin starts a transaction. Changes are made through the map as usual,
+// and reverted by Rollback. Transactions can be nested: rolling back an
+// inner transaction only reverts its own changes, while ending an outer one
+// also ends the transactions inside it.
+func (om *OrderedMap[K, V]) Begin() *Tx[K, V] {
+	j := om.getJournal()
+	tx := &Tx[K, V]{om: om, start: len(j.entries)}
+	j.txs = append(j.txs, tx)
+	return tx
+}
+
+// Commit ends the transaction keeping its changes. When the undo history is
+// enabled, the changes of an outermost transaction are undone as a single
+// step.
+func (tx *Tx[K, V]) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	j := tx.om.journal
+	if tx.end() == 0 {
+		// Outermost transaction
+		j.push(j.entries)
+		j.entries = nil
+	}
+	tx.om.dropJournal()
+	return nil
+}
+
+// Rollback ends the transaction reverting all the changes made since it
+// began, restoring the previous values and order.
+func (tx *Tx[K, V]) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	j := tx.om.journal
+	tx.end()
+
+	j.replaying = true
+	for i := len(j.entries) - 1; i >= tx.start; i-- {
+		tx.om.revert(j.entries[i])
+	}
+	j.replaying = false
+
+	j.entries = j.entries[:tx.start]
+	tx.om.dropJournal()
+	return nil
+}
+
+// End the transaction and the ones inside it, returns its nesting depth
+func (tx *Tx[K, V]) end() int {
+	j := tx.om.journal
+	depth := len(j.txs) - 1
+	for ; j.txs[depth] != tx; depth-- {
+		j.txs[depth].done = true
+	}
+	tx.done = true
+	j.txs = j.txs[:depth]
+	return depth
+}
+
+// EnableHistory starts recording changes so they can be undone and redone.
+// Each change is a step, except for the changes of a transaction which are a
+// single step. At most limit steps are kept, or all of them if limit is not
+// positive.
+func (om *OrderedMap[K, V]) EnableHistory(limit int) {
+	j := om.getJournal()
+	j.history = true
+	j.limit = limit
+	j.trim()
+}
+
+// DisableHistory stops recording changes and drops the undo history
+func (om *OrderedMap[K, V]) DisableHistory() {
+	if om.journal == nil {
+		return
+	}
+	om.journal.history = false
+	om.journal.undo, om.journal.redo = nil, nil
+	om.dropJournal()
+}
+
+// Undo reverts the last step of the history. Returns false if there is
+// nothing to undo or a transaction is open.
+func (om *OrderedMap[K, V]) Undo() bool {
+	j := om.journal
+	if j == nil || len(j.undo) == 0 || len(j.txs) > 0 {
+		return false
+	}
+	step := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+
+	j.replaying = true
+	for i := len(step) - 1; i >= 0; i-- {
+		om.revert(step[i])
+	}
+	j.replaying = false
+
+	j.redo = append(j.redo, step)
+	return true
+}
+
+// Redo applies again the last step reverted by Undo. Returns false if there
+// is nothing to redo or a transaction is open. Any change other than Undo
+// and Redo clears the steps that can be redone.
+func (om *OrderedMap[K, V]) Redo() bool {
+	j := om.journal
+	if j == nil || len(j.redo) == 0 || len(j.txs) > 0 {
+		return false
+	}
+	step := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+
+	j.replaying = true
+	for _, entry := range step {
+		om.apply(entry)
+	}
+	j.replaying = false
+
+	j.undo = append(j.undo, step)
+	return true
+}
+
+// Record the changes of a bulk operation as a single undo step, the returned
+// function must be called when it ends.
+func (om *OrderedMap[K, V]) group() (end func()) {
+	if !om.recording() {
+		return func() {}
+	}
+	tx := om.Begin()
+	return func() { tx.Commit() }
+}
+
+// Return the map journal, creating it if needed
+func (om *OrderedMap[K, V]) getJournal() *journal[K, V] {
+	if om.journal == nil {
+		om.journal = &journal[K, V]{}
+	}
+	return om.journal
+}
+
+// Remove the journal if it isn't needed anymore
+func (om *OrderedMap[K, V]) dropJournal() {
+	if j := om.journal; j != nil && len(j.txs) == 0 && !j.history {
+		om.journal = nil
+	}
+}
+
+// Add a step to the undo history
+func (j *journal[K, V]) push(step []journalEntry[K, V]) {
+	if !j.history || len(step) == 0 {
+		return
+	}
+	j.undo = append(j.undo, step)
+	j.redo = nil
+	j.trim()
+}
+
+// Drop the oldest steps over the limit
+func (j *journal[K, V]) trim() {
+	if j.limit > 0 && len(j.undo) > j.limit {
+		j.undo = append([][]journalEntry[K, V](nil), j.undo[len(j.undo)-j.limit:]...)
+	}
+}
+
+// Whether changes must be recorded
+func (om *OrderedMap[K, V]) recording() bool {
+	return om.journal != nil && !om.journal.replaying
+}
+
+// Record a change
+func (om *OrderedMap[K, V]) record(entry journalEntry[K, V]) {
+	j := om.journal
+	if len(j.txs) > 0 {
+		j.entries = append(j.entries, entry)
+	} else {
+		j.push([]journalEntry[K, V]{entry})
+	}
+}
+
+// Key of a node, nil for the sentinel
+func (om *OrderedMap[K, V]) keyOf(n *node[K, V]) *K {
+	if n == om.root {
+		return nil
+	}
+	key := n.Key
+	return &key
+}
+
+// Node of a key recorded by keyOf
+func (om *OrderedMap[K, V]) nodeOf(key *K) *node[K, V] {
+	if key == nil {
+		return om.root
+	}
+	return om.table[*key]
+}
+
+// Contents before a reset, if it must be recorded
+func (om *OrderedMap[K, V]) resetStart() []Pair[K, V] {
+	if !om.recording() {
+		return nil
+	}
+	return om.Items()
+}
+
+// Record and notify a reset, before are the contents returned by resetStart
+func (om *OrderedMap[K, V]) resetEnd(before []Pair[K, V]) {
+	if om.recording() {
+		om.record(journalEntry[K, V]{op: EventReset, before: before, after: om.Items()})
+	}
+	if om.observers != nil {
+		om.notify(Event[K, V]{Type: EventReset, OldPosition: -1, Position: -1})
+	}
+}
+
+// Replace the map contents
+func (om *OrderedMap[K, V]) replaceItems(items []Pair[K, V]) {
+	NewFromPairs(items...).CopyInto(om)
+}
+
+// Revert a recorded change
+func (om *OrderedMap[K, V]) revert(entry journalEntry[K, V]) {
+	switch entry.op {
+	case EventInsert:
+		om.remove(om.table[entry.key], EventDelete)
+	case EventUpdate:
+		om.update(om.table[entry.key], entry.oldValue)
+	case EventDelete, EventPop:
+		om.insert(entry.key, entry.oldValue, om.nodeOf(entry.oldNext))
+	case EventMove:
+		om.move(om.table[entry.key], om.nodeOf(entry.oldNext))
+	case EventReset:
+		om.replaceItems(entry.before)
+	}
+}
+
+// Apply again a recorded change
+func (om *OrderedMap[K, V]) apply(entry journalEntry[K, V]) {
+	switch entry.op {
+	case EventInsert:
+		om.insert(entry.key, entry.newValue, om.nodeOf(entry.newNext))
+	case EventUpdate:
+		om.update(om.table[entry.key], entry.newValue)
+	case EventDelete, EventPop:
+		om.remove(om.table[entry.key], entry.op)
+	case EventMove:
+		om.move(om.table[entry.key], om.nodeOf(entry.newNext))
+	case EventReset:
+		om.replaceItems(entry.after)
+	}
+}
@@ -0,0 +1,222 @@
+package orderedmap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// Apply a random change to a map
+func randomChange(om *OrderedMap[int, int]) {
+	key := rand.IntN(20)
+	switch rand.IntN(10) {
+	case 0, 1, 2:
+		om.Set(key, rand.IntN(100))
+	case 3:
+		om.Delete(key)
+	case 4:
+		om.Move(key, rand.IntN(2) == 0)
+	case 5:
+		om.MoveAfter(key, rand.IntN(20))
+	case 6:
+		om.InsertBefore(rand.IntN(20), key+100, key)
+	case 7:
+		om.Pop(rand.IntN(2) == 0)
+	case 8:
+		om.DeleteFunc(func(k int, v int) bool { return k == key || v == key })
+	case 9:
+		if rand.IntN(5) == 0 {
+			om.SortByValue(func(a, b int) bool { return a < b })
+		} else {
+			om.Merge(NewFromPairs(Pair[int, int]{key, 1}, Pair[int, int]{key + 1, 2}), MergePolicy(rand.IntN(3)))
+		}
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	om := newIntMap(10)
+	om.EnableIndex()
+	original := om.Clone()
+
+	tx := om.Begin()
+	om.Set(3, 100)
+	om.Delete(5)
+	om.MoveFirst(9)
+	om.InsertAfter(2, 50, 50)
+	om.PopFirst()
+	om.ReverseInPlace()
+	om.Set(20, 20)
+	cursor := om.Cursor()
+	cursor.Next()
+	cursor.Delete()
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if !om.Equal(original, intEq) {
+		t.Error(fmt.Sprintf("Expecting %v received %v", original, om))
+	}
+	if index, _ := om.IndexOf(9); index != 9 {
+		t.Error("Index not restored ", index)
+	}
+	if err := tx.Rollback(); !errors.Is(err, ErrTxDone) {
+		t.Error("Expecting ErrTxDone, received ", err)
+	}
+	if om.journal != nil {
+		t.Error("Journal kept after the transaction ended")
+	}
+
+	// Commit keeps the changes
+	tx = om.Begin()
+	om.Delete(0)
+	if err := tx.Commit(); err != nil || om.Len() != 9 {
+		t.Error("Commit failed ", err, om.Len())
+	}
+	if err := tx.Commit(); !errors.Is(err, ErrTxDone) {
+		t.Error("Expecting ErrTxDone, received ", err)
+	}
+}
+
+func TestTxRandom(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		om := newIntMap(15)
+		for j := 0; j < 10; j++ {
+			randomChange(om)
+		}
+		original := om.Clone()
+
+		tx := om.Begin()
+		for j := 0; j < 20; j++ {
+			randomChange(om)
+		}
+		tx.Rollback()
+
+		if !om.Equal(original, intEq) {
+			t.Fatal(fmt.Sprintf("Expecting %v received %v", original, om))
+		}
+	}
+}
+
+func TestTxNested(t *testing.T) {
+	om := newIntMap(3)
+
+	outer := om.Begin()
+	om.Set(10, 10)
+	inner := om.Begin()
+	om.Delete(0)
+	om.MoveFirst(10)
+	inner.Rollback()
+
+	if fmt.Sprint(om.KeySlice()) != "[0 1 2 10]" {
+		t.Error("Inner rollback reverted the wrong changes ", om)
+	}
+
+	inner = om.Begin()
+	om.Delete(1)
+	inner.Commit()
+	outer.Rollback()
+	if fmt.Sprint(om.KeySlice()) != "[0 1 2]" {
+		t.Error("Outer rollback didn't revert committed inner changes ", om)
+	}
+
+	// Ending an outer transaction ends the inner ones
+	outer = om.Begin()
+	inner = om.Begin()
+	om.Delete(2)
+	outer.Rollback()
+	if err := inner.Commit(); !errors.Is(err, ErrTxDone) || om.Len() != 3 {
+		t.Error("Inner transaction still open ", err, om)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	om := New[string, int]()
+	om.EnableHistory(0)
+
+	om.Set("a", 1)
+	om.Set("b", 2)
+	tx := om.Begin()
+	om.Set("a", 10)
+	om.MoveLast("a")
+	if om.Undo() {
+		t.Error("Undo with an open transaction")
+	}
+	tx.Commit()
+	om.SortByKey(func(a, b string) bool { return a < b })
+
+	states := []string{"OrderedMap[a:10 b:2]", "OrderedMap[b:2 a:10]", "OrderedMap[a:1 b:2]", "OrderedMap[a:1]", "OrderedMap[]"}
+	for _, expected := range states {
+		if om.String() != expected {
+			t.Error(fmt.Sprintf("Undo expecting %v received %v", expected, om))
+		}
+		om.Undo()
+	}
+	if om.Undo() {
+		t.Error("Undo with an empty history")
+	}
+
+	for i := len(states) - 2; i >= 0; i-- {
+		om.Redo()
+		if om.String() != states[i] {
+			t.Error(fmt.Sprintf("Redo expecting %v received %v", states[i], om))
+		}
+	}
+	if om.Redo() {
+		t.Error("Redo with nothing to redo")
+	}
+
+	// A new change clears the redo steps
+	om.Undo()
+	om.Set("c", 3)
+	if om.Redo() {
+		t.Error("Redo after a new change")
+	}
+
+	om.DisableHistory()
+	if om.Undo() || om.journal != nil {
+		t.Error("History not disabled")
+	}
+}
+
+func TestUndoLimit(t *testing.T) {
+	om := New[int, int]()
+	om.EnableHistory(3)
+	for i := 0; i < 10; i++ {
+		om.Set(i, i)
+	}
+
+	undone := 0
+	for om.Undo() {
+		undone++
+	}
+	if undone != 3 || om.Len() != 7 {
+		t.Error("Invalid history limit ", undone, om.Len())
+	}
+
+	// Random changes undone and redone
+	om = newIntMap(15)
+	om.EnableHistory(0)
+	var states []*OrderedMap[int, int]
+	for i := 0; i < 50; i++ {
+		state, steps := om.Clone(), len(om.journal.undo)
+		randomChange(om)
+		if len(om.journal.undo) > steps {
+			// Changes that leave the map unchanged aren't recorded
+			states = append(states, state)
+		}
+	}
+	final := om.Clone()
+
+	for i := len(states) - 1; i >= 0; i-- {
+		om.Undo()
+		if !om.Equal(states[i], intEq) {
+			t.Fatal(fmt.Sprintf("Undo %v expecting %v received %v", i, states[i], om))
+		}
+	}
+	for om.Redo() {
+	}
+	if !om.Equal(final, intEq) {
+		t.Error(fmt.Sprintf("Redo expecting %v received %v", final, om))
+	}
+}
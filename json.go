@@ -0,0 +1,219 @@
+package orderedmap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// DecodeNestedJSON controls how UnmarshalJSON decodes JSON objects nested
+// inside interface{} values. By default they become map[string]interface{}
+// as with encoding/json, when enabled they are decoded into
+// *OrderedMap[string, interface{}] so their key order is also preserved.
+func (om *OrderedMap[K, V]) DecodeNestedJSON(enable bool) {
+	om.nestedJSON = enable
+}
+
+// MarshalJSON implements json.Marshaler, the map is encoded as a JSON object
+// with its keys in insertion order. Keys are encoded following the same rules
+// encoding/json uses for maps: strings are used directly, otherwise keys must
+// implement encoding.TextMarshaler or be integers. A nil map is encoded as
+// null.
+//
+// As every OrderedMap method it has a pointer receiver, so a map stored by
+// value, for example in a struct field, is only encoded as an object when it
+// is addressable: marshal a pointer to the struct, or store a *OrderedMap.
+func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	if om == nil {
+		return []byte("null"), nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	first := true
+	for key, value := range om.All() {
+		if !first {
+			buffer.WriteByte(',')
+		}
+		first = false
+
+		name, err := encodeJSONKey(key)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encoded)
+		buffer.WriteByte(':')
+
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encoded)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keys are inserted in the order
+// they appear in the document. As with plain maps, existing keys are kept
+// and the decoded ones are set on top of them.
+func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	om.init()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("orderedmap: cannot unmarshal %v into OrderedMap", token)
+	}
+	return om.decodeJSONObject(decoder)
+}
+
+// Decode the members of an object whose opening delimiter has already been
+// read, up to and including the closing delimiter.
+func (om *OrderedMap[K, V]) decodeJSONObject(decoder *json.Decoder) error {
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, err := decodeJSONKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if ptr, ok := any(&value).(*interface{}); ok && om.nestedJSON {
+			*ptr, err = decodeNestedJSON(decoder)
+		} else {
+			err = decoder.Decode(&value)
+		}
+		if err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+
+	// Closing delimiter
+	_, err := decoder.Token()
+	return err
+}
+
+// Decode any JSON value, objects are decoded into OrderedMaps
+func decodeNestedJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		nested := New[string, interface{}]()
+		nested.nestedJSON = true
+		if err := nested.decodeJSONObject(decoder); err != nil {
+			return nil, err
+		}
+		return nested, nil
+
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeNestedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+
+	return token, nil
+}
+
+// Convert a map key to a JSON object member name
+func encodeJSONKey(key interface{}) (string, error) {
+	rv := reflect.ValueOf(key)
+	if !rv.IsValid() {
+		return "", fmt.Errorf("orderedmap: unsupported JSON key %v", key)
+	}
+
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+
+	if marshaler, ok := key.(encoding.TextMarshaler); ok {
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "", nil
+		}
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("orderedmap: unsupported JSON key type %T", key)
+}
+
+// Convert a JSON object member name to a map key
+func decodeJSONKey[K comparable](name string) (key K, err error) {
+	if unmarshaler, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err = unmarshaler.UnmarshalText([]byte(name))
+		return
+	}
+
+	rv := reflect.ValueOf(&key).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(name)
+		return
+
+	case reflect.Interface:
+		if rv.NumMethod() == 0 {
+			rv.Set(reflect.ValueOf(name))
+			return
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, parseErr := strconv.ParseInt(name, 10, 64)
+		if parseErr != nil || rv.OverflowInt(n) {
+			err = fmt.Errorf("orderedmap: invalid JSON key %q for type %T", name, key)
+			return
+		}
+		rv.SetInt(n)
+		return
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, parseErr := strconv.ParseUint(name, 10, 64)
+		if parseErr != nil || rv.OverflowUint(n) {
+			err = fmt.Errorf("orderedmap: invalid JSON key %q for type %T", name, key)
+			return
+		}
+		rv.SetUint(n)
+		return
+	}
+
+	err = fmt.Errorf("orderedmap: unsupported JSON key type %T", key)
+	return
+}
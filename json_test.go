@@ -0,0 +1,199 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Key type implementing encoding.TextMarshaler
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d,%d", &p.X, &p.Y)
+	return err
+}
+
+func TestMarshalJSON(t *testing.T) {
+	om := New[string, interface{}]()
+	om.Set("zeta", 1)
+	om.Set("alpha", "two")
+	om.Set("mid", []int{3})
+	om.Set("<html>", nil)
+
+	data, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// HTML characters are escaped as encoding/json does
+	expected := `{"zeta":1,"alpha":"two","mid":[3],"\u003chtml\u003e":null}`
+	if string(data) != expected {
+		t.Error(fmt.Sprintf("Expecting %v received %v", expected, string(data)))
+	}
+
+	// Empty and nil maps
+	if data, _ := json.Marshal(New[string, int]()); string(data) != "{}" {
+		t.Error("Invalid empty map encoding ", string(data))
+	}
+	var nilMap *OrderedMap[string, int]
+	if data, _ := json.Marshal(nilMap); string(data) != "null" {
+		t.Error("Invalid nil map encoding ", string(data))
+	}
+
+	if data, _ := nilMap.MarshalJSON(); string(data) != "null" {
+		t.Error("Invalid nil map MarshalJSON ", string(data))
+	}
+
+	// Maps stored by value are encoded when addressable
+	value := struct{ M OrderedMap[string, int] }{M: *NewFromPairs(Pair[string, int]{"b", 2}, Pair[string, int]{"a", 1})}
+	if data, _ := json.Marshal(&value); string(data) != `{"M":{"b":2,"a":1}}` {
+		t.Error("Invalid addressable map value encoding ", string(data))
+	}
+	pointer := struct{ M *OrderedMap[string, int] }{M: &value.M}
+	if data, _ := json.Marshal(pointer); string(data) != `{"M":{"b":2,"a":1}}` {
+		t.Error("Invalid map pointer encoding ", string(data))
+	}
+	if data, _ := json.Marshal(value); string(data) != `{"M":{}}` {
+		t.Error("Non-addressable map value expected to encode as a struct ", string(data))
+	}
+}
+
+func TestMarshalJSONKeys(t *testing.T) {
+	ints := New[int, bool]()
+	ints.Set(10, true)
+	ints.Set(-2, false)
+	if data, err := json.Marshal(ints); err != nil || string(data) != `{"10":true,"-2":false}` {
+		t.Error("Invalid integer keys encoding ", string(data), err)
+	}
+
+	points := New[point, int]()
+	points.Set(point{1, 2}, 3)
+	if data, err := json.Marshal(points); err != nil || string(data) != `{"1,2":3}` {
+		t.Error("Invalid TextMarshaler keys encoding ", string(data), err)
+	}
+
+	floats := New[float64, int]()
+	floats.Set(1.5, 1)
+	if _, err := json.Marshal(floats); err == nil {
+		t.Error("Float keys should not be supported")
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	om := New[string, int]()
+	if err := json.Unmarshal([]byte(`{"c": 3, "a": 1, "b": 2, "a": 4}`), om); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for key := range om.Keys() {
+		keys = append(keys, key)
+	}
+	if strings.Join(keys, "") != "cab" {
+		t.Error("Keys out of order ", keys)
+	}
+	mapHasKey(t, toAny(om), "a", 4)
+
+	// Non object
+	if err := json.Unmarshal([]byte(`[1, 2]`), om); err == nil {
+		t.Error("Decoded an array into an OrderedMap")
+	}
+
+	// Wrong value type
+	if err := json.Unmarshal([]byte(`{"x": "y"}`), om); err == nil {
+		t.Error("Decoded a string into an int value")
+	}
+}
+
+func TestUnmarshalJSONKeys(t *testing.T) {
+	ints := New[int8, string]()
+	if err := json.Unmarshal([]byte(`{"5": "five", "-1": "minus one"}`), ints); err != nil {
+		t.Fatal(err)
+	}
+	if key, value, _ := ints.GetLast(); key != -1 || value != "minus one" {
+		t.Error("Invalid integer key decoding")
+	}
+	if err := json.Unmarshal([]byte(`{"500": "overflow"}`), ints); err == nil {
+		t.Error("Decoded an overflowing key")
+	}
+
+	points := New[point, int]()
+	if err := json.Unmarshal([]byte(`{"3,4": 7}`), points); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := points.Get(point{3, 4}); !ok || value != 7 {
+		t.Error("Invalid TextUnmarshaler key decoding")
+	}
+}
+
+func TestUnmarshalJSONNested(t *testing.T) {
+	document := `{"b": {"y": 1, "x": [{"q": true, "p": null}]}, "a": "text"}`
+
+	// Default behaviour matches encoding/json
+	om := New[string, interface{}]()
+	if err := json.Unmarshal([]byte(document), om); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := om.Get("b"); fmt.Sprintf("%T", value) != "map[string]interface {}" {
+		t.Error("Expecting a plain map, received ", value)
+	}
+
+	// Nested OrderedMaps
+	om = New[string, interface{}]()
+	om.DecodeNestedJSON(true)
+	if err := json.Unmarshal([]byte(document), om); err != nil {
+		t.Fatal(err)
+	}
+	value, _ := om.Get("b")
+	nested, ok := value.(*OrderedMap[string, interface{}])
+	if !ok {
+		t.Fatal("Expecting a nested OrderedMap, received ", value)
+	}
+	if key, _, _ := nested.GetFirst(); key != "y" {
+		t.Error("Nested keys out of order")
+	}
+
+	// Encoding returns the same document
+	data, err := json.Marshal(om)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"b":{"y":1,"x":[{"q":true,"p":null}]},"a":"text"}` {
+		t.Error("Invalid round trip ", string(data))
+	}
+}
+
+// Maps as struct fields are allocated by the decoder
+func TestUnmarshalJSONField(t *testing.T) {
+	var document struct {
+		Ptr   *OrderedMap[string, int]
+		Value OrderedMap[string, int]
+	}
+
+	data := `{"Ptr": {"b": 2, "a": 1}, "Value": {"d": 4, "c": 3}}`
+	if err := json.Unmarshal([]byte(data), &document); err != nil {
+		t.Fatal(err)
+	}
+	if key, _, _ := document.Ptr.GetFirst(); key != "b" {
+		t.Error("Invalid pointer field decoding")
+	}
+	if key, _, _ := document.Value.GetFirst(); key != "d" {
+		t.Error("Invalid value field decoding")
+	}
+}
+
+// Copy a typed map into an interface{} map to use the test helpers
+func toAny[K comparable, V any](om *OrderedMap[K, V]) *OrderedMap[interface{}, interface{}] {
+	result := NewOrderedMap()
+	for key, value := range om.All() {
+		result.Set(key, value)
+	}
+	return result
+}
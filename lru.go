@@ -0,0 +1,145 @@
+package orderedmap
+
+import "iter"
+
+// LRUCache is a fixed capacity cache that evicts the least recently used
+// entry when full. It is built on an OrderedMap kept in recency order, from
+// the least to the most recently used key.
+//
+// LRUCache is not safe for concurrent use.
+type LRUCache[K comparable, V any] struct {
+	om       *OrderedMap[K, V]
+	capacity int
+	onEvict  func(key K, value V)
+	stats    LRUStats
+}
+
+// LRUStats are the LRUCache access counters
+type LRUStats struct {
+	Hits      uint64 // Get calls that found the key
+	Misses    uint64 // Get calls that didn't find the key
+	Evictions uint64 // Entries removed to make room for new ones
+}
+
+// NewLRUCache creates an empty cache holding at most capacity entries,
+// it panics if capacity is not positive.
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	if capacity <= 0 {
+		panic("orderedmap: LRUCache capacity must be positive")
+	}
+	return &LRUCache[K, V]{
+		om:       New[K, V](),
+		capacity: capacity,
+	}
+}
+
+// OnEvict sets a function called with every entry evicted because the
+// cache was full, it isn't called for entries removed with Delete.
+func (c *LRUCache[K, V]) OnEvict(fn func(key K, value V)) {
+	c.onEvict = fn
+}
+
+// Len returns the number of entries in the cache
+func (c *LRUCache[K, V]) Len() int {
+	return c.om.Len()
+}
+
+// Capacity returns the maximum number of entries in the cache
+func (c *LRUCache[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Resize changes the cache capacity, evicting the least recently used
+// entries if it no longer fits. It panics if capacity is not positive.
+func (c *LRUCache[K, V]) Resize(capacity int) {
+	if capacity <= 0 {
+		panic("orderedmap: LRUCache capacity must be positive")
+	}
+	c.capacity = capacity
+	for c.om.Len() > c.capacity {
+		key, value, _ := c.om.PopFirst()
+		c.evicted(key, value)
+	}
+}
+
+// Get returns the value for a key and marks it as the most recently used
+func (c *LRUCache[K, V]) Get(key K) (value V, ok bool) {
+	node, ok := c.om.table[key]
+	if !ok {
+		c.stats.Misses++
+		return
+	}
+
+	c.stats.Hits++
+	c.om.unlink(node)
+	c.om.linkBefore(node, c.om.root)
+	return node.Value, true
+}
+
+// Peek returns the value for a key without changing its recency or the
+// cache counters.
+func (c *LRUCache[K, V]) Peek(key K) (value V, ok bool) {
+	return c.om.Get(key)
+}
+
+// Set the value for a key and mark it as the most recently used, if the key
+// is new and the cache is full the least recently used entry is evicted.
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	om := c.om
+	if node, ok := om.table[key]; ok {
+		node.Value = value
+		om.unlink(node)
+		om.linkBefore(node, om.root)
+		return
+	}
+
+	if om.Len() < c.capacity {
+		om.Set(key, value)
+		return
+	}
+
+	// Reuse the evicted node for the new entry
+	node := om.root.Next
+	om.unlink(node)
+	delete(om.table, node.Key)
+	evictedKey, evictedValue := node.Key, node.Value
+
+	node.Key, node.Value = key, value
+	om.linkBefore(node, om.root)
+	om.table[key] = node
+
+	c.evicted(evictedKey, evictedValue)
+}
+
+// Delete a key from the cache, returns false if it wasn't present
+func (c *LRUCache[K, V]) Delete(key K) (ok bool) {
+	if _, ok = c.om.table[key]; ok {
+		c.om.Delete(key)
+	}
+	return
+}
+
+// Purge removes all the entries from the cache without calling OnEvict, the
+// counters are left unchanged.
+func (c *LRUCache[K, V]) Purge() {
+	c.om = New[K, V]()
+}
+
+// Stats returns the cache access counters
+func (c *LRUCache[K, V]) Stats() LRUStats {
+	return c.stats
+}
+
+// All returns an iterator over the cache entries from the least to the most
+// recently used, without changing their recency.
+func (c *LRUCache[K, V]) All() iter.Seq2[K, V] {
+	return c.om.All()
+}
+
+// Record an eviction and notify it
+func (c *LRUCache[K, V]) evicted(key K, value V) {
+	c.stats.Evictions++
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
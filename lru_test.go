@@ -0,0 +1,130 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache[string, int](2)
+
+	var evicted []string
+	cache.OnEvict(func(key string, value int) {
+		evicted = append(evicted, fmt.Sprintf("%v:%v", key, value))
+	})
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a") // b is now the least recently used
+	cache.Set("c", 3)
+
+	if fmt.Sprint(evicted) != "[b:2]" {
+		t.Error("Expecting b evicted, evicted ", evicted)
+	}
+	if _, ok := cache.Peek("b"); ok {
+		t.Error("Evicted key still in cache")
+	}
+	if cache.Len() != 2 || cache.Capacity() != 2 {
+		t.Error("Invalid cache size ", cache.Len(), cache.Capacity())
+	}
+
+	// Updating an existing key doesn't evict
+	cache.Set("a", 10)
+	if value, ok := cache.Get("a"); value != 10 || !ok {
+		t.Error(fmt.Sprintf("Expecting 10, true -> Returned %v %v", value, ok))
+	}
+	if len(evicted) != 1 {
+		t.Error("Updating a key evicted ", evicted)
+	}
+
+	// a was used last so c goes next
+	cache.Set("d", 4)
+	if fmt.Sprint(evicted) != "[b:2 c:3]" {
+		t.Error("Expecting c evicted, evicted ", evicted)
+	}
+}
+
+func TestLRUCachePeek(t *testing.T) {
+	cache := NewLRUCache[int, int](2)
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	// Peek doesn't change recency, so 1 is still evicted first
+	if value, ok := cache.Peek(1); value != 1 || !ok {
+		t.Error(fmt.Sprintf("Expecting 1, true -> Returned %v %v", value, ok))
+	}
+	cache.Set(3, 3)
+	if _, ok := cache.Peek(1); ok {
+		t.Error("Peek changed the key recency")
+	}
+
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Error("Peek changed the counters ", stats)
+	}
+}
+
+func TestLRUCacheStats(t *testing.T) {
+	cache := NewLRUCache[int, int](3)
+	for k := 0; k < 5; k++ {
+		cache.Set(k, k)
+	}
+	cache.Get(4)
+	cache.Get(3)
+	cache.Get(0)
+
+	expected := LRUStats{Hits: 2, Misses: 1, Evictions: 2}
+	if stats := cache.Stats(); stats != expected {
+		t.Error(fmt.Sprintf("Expecting %+v received %+v", expected, stats))
+	}
+
+	// Keys from the least to the most recently used
+	var keys []int
+	for key := range cache.All() {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[2 4 3]" {
+		t.Error("Invalid recency order ", keys)
+	}
+}
+
+func TestLRUCacheDeleteResize(t *testing.T) {
+	cache := NewLRUCache[int, int](4)
+	evictions := 0
+	cache.OnEvict(func(key int, value int) {
+		evictions++
+	})
+
+	for k := 0; k < 4; k++ {
+		cache.Set(k, k)
+	}
+
+	if !cache.Delete(1) || cache.Delete(1) {
+		t.Error("Delete returned an invalid result")
+	}
+
+	cache.Resize(1)
+	if cache.Len() != 1 || evictions != 2 {
+		t.Error("Resize didn't evict entries ", cache.Len(), evictions)
+	}
+	if _, ok := cache.Peek(3); !ok {
+		t.Error("Resize evicted the most recently used key")
+	}
+
+	cache.Purge()
+	if cache.Len() != 0 || evictions != 2 {
+		t.Error("Purge didn't remove entries")
+	}
+	cache.Set(5, 5)
+	if value, ok := cache.Get(5); value != 5 || !ok {
+		t.Error("Cache unusable after Purge")
+	}
+}
+
+func TestLRUCacheInvalidCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expecting panic with zero capacity")
+		}
+	}()
+	NewLRUCache[int, int](0)
+}
@@ -1,14 +1,16 @@
 package orderedmap
 
 // An element of an OrderedDict, forms a linked list ordered by insertion time
-type node struct {
-	Key   interface{}
-	Value interface{}
-	Next  *node
-	Prev  *node
+type node[K comparable, V any] struct {
+	Key   K
+	Value V
+	Next  *node[K, V]
+	Prev  *node[K, V]
+
+	rank *rankNode[K, V] // Position index entry, nil when not indexed
 }
 
 // Create new node
-func newNode(key interface{}, value interface{}, next *node, prev *node) *node {
-	return &node{key, value, next, prev}
+func newNode[K comparable, V any](key K, value V, next *node[K, V], prev *node[K, V]) *node[K, V] {
+	return &node[K, V]{Key: key, Value: value, Next: next, Prev: prev}
 }
@@ -0,0 +1,268 @@
+package orderedmap
+
+import (
+	"slices"
+	"sync"
+)
+
+// EventType is the kind of change described by an Event
+type EventType int
+
+const (
+	// EventInsert is a new key added to the map
+	EventInsert EventType = iota
+
+	// EventUpdate is the value of an existing key replaced
+	EventUpdate
+
+	// EventDelete is a key deleted from the map
+	EventDelete
+
+	// EventPop is a key removed from either end by Pop
+	EventPop
+
+	// EventMove is an existing key moved to another position
+	EventMove
+
+	// EventReset is the whole map changed at once, by a sort or a copy, so
+	// subscribers must read the map again. It carries no key.
+	EventReset
+)
+
+func (et EventType) String() string {
+	switch et {
+	case EventInsert:
+		return "insert"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	case EventPop:
+		return "pop"
+	case EventMove:
+		return "move"
+	case EventReset:
+		return "reset"
+	}
+	return "unknown"
+}
+
+// Event describes a change to an OrderedMap. Positions are the element index
+// in the map order before and after the change, -1 when the key wasn't or
+// isn't in the map.
+type Event[K comparable, V any] struct {
+	Type        EventType
+	Key         K
+	OldValue    V // Value before an update, delete, pop or move
+	NewValue    V // Value after an insert, update or move
+	OldPosition int
+	Position    int
+}
+
+// Subscribers of a map, called in subscription order. The slice is replaced
+// rather than modified, so unsubscribing while notifying is safe.
+type observers[K comparable, V any] struct {
+	subscribers []subscriber[K, V]
+	nextID      uint64
+}
+
+type subscriber[K comparable, V any] struct {
+	id uint64
+	fn func(Event[K, V])
+}
+
+// Subscribe calls fn with an Event after every change to the map, until the
+// returned unsubscribe function is called. fn is called synchronously by the
+// goroutine that changed the map, and must not modify it.
+//
+// Events carry the position of the key, while there are subscribers every
+// change costs O(n) to find it unless the map index is enabled.
+func (om *OrderedMap[K, V]) Subscribe(fn func(event Event[K, V])) (unsubscribe func()) {
+	if om.observers == nil {
+		om.observers = &observers[K, V]{}
+	}
+	obs := om.observers
+	id := obs.nextID
+	obs.nextID++
+	obs.subscribers = append(slices.Clip(obs.subscribers), subscriber[K, V]{id: id, fn: fn})
+
+	return func() {
+		if om.observers != obs {
+			// Already removed with every other subscriber
+			return
+		}
+		obs.subscribers = slices.DeleteFunc(slices.Clone(obs.subscribers), func(s subscriber[K, V]) bool {
+			return s.id == id
+		})
+		if len(obs.subscribers) == 0 {
+			om.observers = nil
+		}
+	}
+}
+
+// SubscribeChan returns a channel that receives an Event after every change
+// to the map, with space for buffer events. When the buffer is full the
+// change that sent the event blocks until the subscriber receives it or
+// unsubscribes, so the subscriber must keep reading until it calls
+// unsubscribe.
+//
+// Unlike the function returned by Subscribe, unsubscribe can be called from
+// any goroutine, even while a change is blocked sending an event. It stops
+// the delivery and closes the channel, and the subscriber is removed from the
+// map on its next change.
+func (om *OrderedMap[K, V]) SubscribeChan(buffer int) (events <-chan Event[K, V], unsubscribe func()) {
+	ch := make(chan Event[K, V], buffer)
+	stop := make(chan struct{})
+
+	// Held for reading while sending, so the channel isn't closed until any
+	// send in progress gives up.
+	var mu sync.RWMutex
+	closed := false
+
+	var cancel func()
+	cancel = om.Subscribe(func(event Event[K, V]) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			// Removed here, by the goroutine changing the map
+			cancel()
+			return
+		}
+		select {
+		case ch <- event:
+		case <-stop:
+		}
+	})
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(stop)
+			mu.Lock()
+			closed = true
+			close(ch)
+			mu.Unlock()
+		})
+	}
+}
+
+// Send an event to the subscribers
+func (om *OrderedMap[K, V]) notify(event Event[K, V]) {
+	for _, s := range om.observers.subscribers {
+		s.fn(event)
+	}
+}
+
+// Position of a node in the list
+func (om *OrderedMap[K, V]) positionOf(n *node[K, V]) (position int) {
+	if om.index != nil {
+		return om.index.rankOf(n)
+	}
+	for c := om.root.Next; c != n; c = c.Next {
+		position++
+	}
+	return
+}
+
+// Insert a new key before a node, which can be the sentinel
+func (om *OrderedMap[K, V]) insert(key K, value V, mark *node[K, V]) {
+	node := newNode[K, V](key, value, nil, nil)
+	om.linkBefore(node, mark)
+	om.table[key] = node
+
+	if om.recording() {
+		om.record(journalEntry[K, V]{op: EventInsert, key: key, newValue: value, newNext: om.keyOf(node.Next)})
+	}
+	if om.observers != nil {
+		om.notify(Event[K, V]{
+			Type:        EventInsert,
+			Key:         key,
+			NewValue:    value,
+			OldPosition: -1,
+			Position:    om.positionOf(node),
+		})
+	}
+}
+
+// Replace the value of a node
+func (om *OrderedMap[K, V]) update(n *node[K, V], value V) {
+	oldValue := n.Value
+	n.Value = value
+
+	if om.recording() {
+		om.record(journalEntry[K, V]{op: EventUpdate, key: n.Key, oldValue: oldValue, newValue: value})
+	}
+	if om.observers != nil {
+		position := om.positionOf(n)
+		om.notify(Event[K, V]{
+			Type:        EventUpdate,
+			Key:         n.Key,
+			OldValue:    oldValue,
+			NewValue:    value,
+			OldPosition: position,
+			Position:    position,
+		})
+	}
+}
+
+// Remove a node from the map, eventType is EventDelete or EventPop
+func (om *OrderedMap[K, V]) remove(n *node[K, V], eventType EventType) {
+	position := -1
+	if om.observers != nil {
+		position = om.positionOf(n)
+	}
+	oldNext := n.Next
+
+	om.unlink(n)
+	delete(om.table, n.Key)
+
+	if om.recording() {
+		om.record(journalEntry[K, V]{op: eventType, key: n.Key, oldValue: n.Value, oldNext: om.keyOf(oldNext)})
+	}
+	if om.observers != nil {
+		om.notify(Event[K, V]{
+			Type:        eventType,
+			Key:         n.Key,
+			OldValue:    n.Value,
+			OldPosition: position,
+			Position:    -1,
+		})
+	}
+}
+
+// Move a node just before mark, which can be the sentinel
+func (om *OrderedMap[K, V]) move(n *node[K, V], mark *node[K, V]) {
+	if mark == n || mark == n.Next {
+		// Already in place
+		return
+	}
+	position := -1
+	if om.observers != nil {
+		position = om.positionOf(n)
+	}
+	oldNext := n.Next
+
+	om.unlink(n)
+	om.linkBefore(n, mark)
+
+	if om.recording() {
+		om.record(journalEntry[K, V]{
+			op:       EventMove,
+			key:      n.Key,
+			oldValue: n.Value,
+			newValue: n.Value,
+			oldNext:  om.keyOf(oldNext),
+			newNext:  om.keyOf(mark),
+		})
+	}
+	if om.observers != nil {
+		om.notify(Event[K, V]{
+			Type:        EventMove,
+			Key:         n.Key,
+			OldValue:    n.Value,
+			NewValue:    n.Value,
+			OldPosition: position,
+			Position:    om.positionOf(n),
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Record the events of a map as strings
+func recordEvents[K comparable, V any](om *OrderedMap[K, V]) (events *[]string, unsubscribe func()) {
+	events = new([]string)
+	unsubscribe = om.Subscribe(func(e Event[K, V]) {
+		*events = append(*events, fmt.Sprintf("%v %v %v->%v %v->%v",
+			e.Type, e.Key, e.OldValue, e.NewValue, e.OldPosition, e.Position))
+	})
+	return
+}
+
+func TestSubscribe(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+
+	events, unsubscribe := recordEvents(om)
+	om.Set("b", 2)
+	om.Set("a", 10)
+	om.InsertBefore("a", "c", 3)
+	om.MoveLast("c")
+	om.MoveLast("c") // Already last, no event
+	om.MoveAfter("a", "b")
+	om.Delete("b")
+	om.Delete("z")
+	om.PopFirst()
+	om.SortByKey(func(a, b string) bool { return a < b })
+
+	expected := []string{
+		"insert b 0->2 -1->1",
+		"update a 1->10 0->0",
+		"insert c 0->3 -1->0",
+		"move c 3->3 0->2",
+		"move a 10->10 0->1",
+		"delete b 2->0 0->-1",
+		"pop a 10->0 0->-1",
+		"reset  0->0 -1->-1",
+	}
+	if fmt.Sprint(*events) != fmt.Sprint(expected) {
+		t.Error(fmt.Sprintf("Expecting %v\nreceived %v", expected, *events))
+	}
+
+	unsubscribe()
+	unsubscribe()
+	om.Set("d", 4)
+	if len(*events) != len(expected) {
+		t.Error("Event received after unsubscribing")
+	}
+}
+
+func TestSubscribeBulk(t *testing.T) {
+	om := newIntMap(6)
+	om.EnableIndex()
+	events, _ := recordEvents(om)
+
+	// Events from operations built on other methods
+	om.DeleteFunc(func(key int, value int) bool { return key%3 == 0 })
+	om.Merge(NewFromPairs(Pair[int, int]{1, 100}, Pair[int, int]{7, 49}), MergeOverwriteMove)
+
+	cursor := om.Cursor()
+	cursor.Next()
+	cursor.SetValue(-1)
+
+	expected := []string{
+		"delete 0 0->0 0->-1",
+		"delete 3 9->0 2->-1",
+		"update 1 1->100 0->0",
+		"move 1 100->100 0->3",
+		"insert 7 0->49 -1->4",
+		"update 2 4->-1 0->0",
+	}
+	if fmt.Sprint(*events) != fmt.Sprint(expected) {
+		t.Error(fmt.Sprintf("Expecting %v\nreceived %v", expected, *events))
+	}
+}
+
+func TestSubscribeMultiple(t *testing.T) {
+	om := New[int, int]()
+
+	var order []string
+	var unsubscribeFirst func()
+	unsubscribeFirst = om.Subscribe(func(e Event[int, int]) {
+		order = append(order, "first")
+		unsubscribeFirst() // Unsubscribing while notified
+	})
+	om.Subscribe(func(e Event[int, int]) {
+		order = append(order, "second")
+	})
+
+	om.Set(1, 1)
+	om.Set(2, 2)
+	if fmt.Sprint(order) != "[first second second]" {
+		t.Error("Invalid subscriber calls ", order)
+	}
+}
+
+func TestSubscribeChan(t *testing.T) {
+	om := New[string, int]()
+	events, unsubscribe := om.SubscribeChan(2)
+
+	done := make(chan []string)
+	go func() {
+		var received []string
+		for event := range events {
+			received = append(received, fmt.Sprint(event.Type, " ", event.Key))
+		}
+		done <- received
+	}()
+
+	// More events than the buffer size
+	for _, key := range []string{"a", "b", "c", "d"} {
+		om.Set(key, 0)
+	}
+	om.Delete("a")
+	unsubscribe()
+	unsubscribe()
+
+	received := <-done
+	if fmt.Sprint(received) != "[insert a insert b insert c insert d delete a]" {
+		t.Error("Invalid events received ", received)
+	}
+}
+
+// Unsubscribing from another goroutine while events are being sent
+func TestSubscribeChanUnsubscribe(t *testing.T) {
+	for buffer := 0; buffer < 3; buffer++ {
+		om := New[int, int]()
+		events, unsubscribe := om.SubscribeChan(buffer)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-events
+			unsubscribe()
+		}()
+
+		for i := 0; i < 100; i++ {
+			om.Set(i, i)
+		}
+		<-done
+		unsubscribe()
+
+		// Removed on the next change
+		om.Set(100, 100)
+		if om.observers != nil {
+			t.Error("Subscriber not removed after unsubscribing")
+		}
+	}
+}
@@ -1,55 +1,95 @@
 // Package orderedmap is a Go implementation of Python's OrderedDict class, a map
 // that preserves the order of insertion, so key:value pairs can be iterated in
 // the order they where added.
-// It can also be used as a stack (LIFO) or queue (FIFO).
+// It can also be used as a stack (LIFO) or queue (FIFO), and Queue provides
+// bounded queues of unique keys with blocking operations.
 package orderedmap
 
 import "fmt"
 
 // OrderedMap class
-type OrderedMap struct {
-	table map[interface{}]*node
-	root  *node
+type OrderedMap[K comparable, V any] struct {
+	table map[K]*node[K, V]
+	root  *node[K, V]
+
+	index      *rankIndex[K, V] // Optional position index
+	observers  *observers[K, V] // Change subscribers
+	journal    *journal[K, V]   // Changes of transactions and undo history
+	nestedJSON bool             // Decode nested JSON objects into OrderedMaps
 }
 
-// NewOrderedMap creates an empty OrderedMap
-func NewOrderedMap() *OrderedMap {
-	root := newNode(nil, nil, nil, nil) // sentinel Node
-	root.Next, root.Prev = root, root
+// New creates an empty OrderedMap with keys of type K and values of type V
+func New[K comparable, V any]() *OrderedMap[K, V] {
+	om := &OrderedMap[K, V]{}
+	om.init()
+	return om
+}
 
-	om := &OrderedMap{
-		table: make(map[interface{}]*node),
-		root:  root,
+// Initialize the table and sentinel node of a zero value OrderedMap, maps
+// allocated by decoders don't go through New.
+func (om *OrderedMap[K, V]) init() {
+	if om.root != nil {
+		return
 	}
-	return om
+
+	var key K
+	var value V
+	root := newNode[K, V](key, value, nil, nil) // sentinel Node
+	root.Next, root.Prev = root, root
+
+	om.table = make(map[K]*node[K, V])
+	om.root = root
+}
+
+// NewOrderedMap creates an empty OrderedMap that accepts keys and values of
+// any type, as the map did before it was type parameterized.
+func NewOrderedMap() *OrderedMap[interface{}, interface{}] {
+	return New[interface{}, interface{}]()
 }
 
 // Len returns the number of elements in the Map
-func (om *OrderedMap) Len() int {
+func (om *OrderedMap[K, V]) Len() int {
 	return len(om.table)
 }
 
+// Link a node into the list just before mark
+func (om *OrderedMap[K, V]) linkBefore(n *node[K, V], mark *node[K, V]) {
+	n.Next = mark
+	n.Prev = mark.Prev
+	mark.Prev.Next = n
+	mark.Prev = n
+
+	if om.index != nil {
+		om.index.insertBefore(n, mark)
+	}
+}
+
+// Unlink a node from the list, its Next and Prev pointers are left unchanged
+// so iterators positioned on it can keep going.
+func (om *OrderedMap[K, V]) unlink(n *node[K, V]) {
+	n.Next.Prev = n.Prev
+	n.Prev.Next = n.Next
+
+	if om.index != nil {
+		om.index.remove(n)
+	}
+}
+
 // Set the key value, if the key overwrites an existing entry, the original
 // insertion position is left unchanged, otherwise the key is inserted at the end.
-func (om *OrderedMap) Set(key interface{}, value interface{}) {
+func (om *OrderedMap[K, V]) Set(key K, value V) {
 	if node, ok := om.table[key]; !ok {
 		// New Node
-		root := om.root
-		node := newNode(key, value, root, root.Prev)
-		root.Prev.Next = node
-		root.Prev = node
-		om.table[key] = node
+		om.insert(key, value, om.root)
 	} else {
 		// Update existing node value
-		node.Value = value
+		om.update(node, value)
 	}
 }
 
 // Get the value of an existing key, leaving the map unchanged
-func (om *OrderedMap) Get(key interface{}) (value interface{}, ok bool) {
-	if node, isOk := om.table[key]; !isOk {
-		value, ok = nil, false
-	} else {
+func (om *OrderedMap[K, V]) Get(key K) (value V, ok bool) {
+	if node, isOk := om.table[key]; isOk {
 		value, ok = node.Value, true
 	}
 	return
@@ -57,10 +97,8 @@ func (om *OrderedMap) Get(key interface{}) (value interface{}, ok bool) {
 
 // GetLast return the key and value for the last element added, leaving
 // the map unchanged
-func (om *OrderedMap) GetLast() (key interface{}, value interface{}, ok bool) {
-	if len(om.table) == 0 {
-		key, value, ok = nil, nil, false
-	} else {
+func (om *OrderedMap[K, V]) GetLast() (key K, value V, ok bool) {
+	if len(om.table) != 0 {
 		node := om.root.Prev
 		key, value, ok = node.Key, node.Value, true
 	}
@@ -68,10 +106,8 @@ func (om *OrderedMap) GetLast() (key interface{}, value interface{}, ok bool) {
 }
 
 // GetFirst returns the key and value for the first element, leaving the map unchanged
-func (om *OrderedMap) GetFirst() (key interface{}, value interface{}, ok bool) {
-	if len(om.table) == 0 {
-		key, value, ok = nil, nil, false
-	} else {
+func (om *OrderedMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	if len(om.table) != 0 {
 		node := om.root.Next
 		key, value, ok = node.Key, node.Value, true
 	}
@@ -79,91 +115,77 @@ func (om *OrderedMap) GetFirst() (key interface{}, value interface{}, ok bool) {
 }
 
 // Delete a key:value pair from the map.
-func (om *OrderedMap) Delete(key interface{}) {
+func (om *OrderedMap[K, V]) Delete(key K) {
 	if node, ok := om.table[key]; ok {
-		node.Next.Prev = node.Prev
-		node.Prev.Next = node.Next
-
-		delete(om.table, key)
+		om.remove(node, EventDelete)
 	}
 }
 
 // Pop and return key:value for the newest or oldest element on the OrderedMap
-func (om *OrderedMap) Pop(last bool) (key interface{}, value interface{}, ok bool) {
-	if last {
-		key, value, ok = om.GetLast()
-	} else {
-		key, value, ok = om.GetFirst()
+func (om *OrderedMap[K, V]) Pop(last bool) (key K, value V, ok bool) {
+	if om.Len() == 0 {
+		return
 	}
 
-	if ok {
-		om.Delete(key)
+	node := om.root.Next
+	if last {
+		node = om.root.Prev
 	}
-	return
+	om.remove(node, EventPop)
+	return node.Key, node.Value, true
 }
 
 // PopLast is a shortcut to Pop the last element
-func (om *OrderedMap) PopLast() (key interface{}, value interface{}, ok bool) {
+func (om *OrderedMap[K, V]) PopLast() (key K, value V, ok bool) {
 	return om.Pop(true)
 }
 
 // PopFirst is a shortcut to Pop the first element
-func (om *OrderedMap) PopFirst() (key interface{}, value interface{}, ok bool) {
+func (om *OrderedMap[K, V]) PopFirst() (key K, value V, ok bool) {
 	return om.Pop(false)
 }
 
 // Move an existing key to either the end of the OrderedMap
-func (om *OrderedMap) Move(key interface{}, last bool) (ok bool) {
+func (om *OrderedMap[K, V]) Move(key K, last bool) (ok bool) {
 
-	var moved *node
-
-	// Remove from current position
-	anode, ok := om.table[key]
+	moved, ok := om.table[key]
 	if !ok {
 		return false
 	}
 
-	anode.Next.Prev = anode.Prev
-	anode.Prev.Next = anode.Next
-	moved = anode
-
 	// Insert at the start or end
-	root := om.root
 	if last {
-		moved.Next = root
-		moved.Prev = root.Prev
-		root.Prev.Next = moved
-		root.Prev = moved
+		om.move(moved, om.root)
 	} else {
-		moved.Prev = root
-		moved.Next = root.Next
-		root.Next.Prev = moved
-		root.Next = moved
+		om.move(moved, om.root.Next)
 	}
 
 	return true
 }
 
 // MoveLast is a shortcut to Move a key to the end o the map
-func (om *OrderedMap) MoveLast(key interface{}) (ok bool) {
+func (om *OrderedMap[K, V]) MoveLast(key K) (ok bool) {
 	return om.Move(key, true)
 }
 
 // MoveFirst is a shortcut to Move a key to the beginning of the map
-func (om *OrderedMap) MoveFirst(key interface{}) (ok bool) {
+func (om *OrderedMap[K, V]) MoveFirst(key K) (ok bool) {
 	return om.Move(key, false)
 }
 
 // MapIterator is a iterator over an OrderedMap
-type MapIterator struct {
-	curr    *node
-	root    *node
+type MapIterator[K comparable, V any] struct {
+	curr    *node[K, V]
+	root    *node[K, V]
 	reverse bool
+
+	// Optional end of a range, returns true for the first node past it
+	past func(n *node[K, V]) bool
 }
 
 // Iter creates a map iterator
-func (om *OrderedMap) Iter() *MapIterator {
-	return &MapIterator{
+func (om *OrderedMap[K, V]) Iter() *MapIterator[K, V] {
+	return &MapIterator[K, V]{
 		curr:    om.root,
 		root:    om.root,
 		reverse: false,
@@ -171,8 +193,8 @@ func (om *OrderedMap) Iter() *MapIterator {
 }
 
 // IterReverse creates a reverse order map iterator
-func (om *OrderedMap) IterReverse() *MapIterator {
-	return &MapIterator{
+func (om *OrderedMap[K, V]) IterReverse() *MapIterator[K, V] {
+	return &MapIterator[K, V]{
 		curr:    om.root,
 		root:    om.root,
 		reverse: true,
@@ -180,11 +202,11 @@ func (om *OrderedMap) IterReverse() *MapIterator {
 }
 
 // Next key:value pair
-func (mi *MapIterator) Next() (key interface{}, value interface{}, ok bool) {
+func (mi *MapIterator[K, V]) Next() (key K, value V, ok bool) {
 
 	// Already finished
 	if mi.curr == nil {
-		return nil, nil, false
+		return
 	}
 
 	// Advance pointer
@@ -195,9 +217,8 @@ func (mi *MapIterator) Next() (key interface{}, value interface{}, ok bool) {
 	}
 
 	// This is the last iteration
-	if mi.curr == mi.root {
+	if mi.curr == mi.root || (mi.past != nil && mi.past(mi.curr)) {
 		mi.curr = nil
-		key, value, ok = nil, nil, false
 	} else {
 		key, value, ok = mi.curr.Key, mi.curr.Value, true
 	}
@@ -205,15 +226,8 @@ func (mi *MapIterator) Next() (key interface{}, value interface{}, ok bool) {
 	return
 }
 
-// String interface
-func (om *OrderedMap) String() string {
-	buffer := make([]string, om.Len())
-
-	iter := om.Iter()
-	index := 0
-	for key, value, ok := iter.Next(); ok; key, value, ok = iter.Next() {
-		buffer[index] = fmt.Sprintf("%v:%v, ", key, value)
-		index++
-	}
-	return fmt.Sprintf("OrderedMap%v", buffer)
+// String returns the map contents in the same format as a built-in map,
+// prefixed by the type name instead of map: OrderedMap[a:1 b:2]
+func (om *OrderedMap[K, V]) String() string {
+	return fmt.Sprint(om)
 }
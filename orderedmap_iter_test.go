@@ -10,14 +10,14 @@ type KeyValue struct {
 	value int
 }
 
-type VisitorFunc func(t *testing.T, om *OrderedMap, iter_num int, key interface{}, value interface{})
+type VisitorFunc func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int, key interface{}, value interface{})
 
 //
 // Visitor func
 // t
 // om
 // in -> iteration number starting from 0
-func IterVisitor(t *testing.T, om *OrderedMap, visitor VisitorFunc) {
+func IterVisitor(t *testing.T, om *OrderedMap[interface{}, interface{}], visitor VisitorFunc) {
 
 	iterNum := 0
 	iter := om.Iter()
@@ -27,7 +27,7 @@ func IterVisitor(t *testing.T, om *OrderedMap, visitor VisitorFunc) {
 	}
 }
 
-func IterReverseVisitor(t *testing.T, om *OrderedMap, visitor VisitorFunc) {
+func IterReverseVisitor(t *testing.T, om *OrderedMap[interface{}, interface{}], visitor VisitorFunc) {
 
 	iterNum := 0
 	iter := om.IterReverse()
@@ -41,9 +41,9 @@ func IterReverseVisitor(t *testing.T, om *OrderedMap, visitor VisitorFunc) {
 // ifunc -> function applied during each iteration
 // values -> array of values expected during iteration
 // revers -> iterate in reverse
-func ApplyIterFunc(t *testing.T, om *OrderedMap, ifunc VisitorFunc, values []KeyValue, reverse bool) {
+func ApplyIterFunc(t *testing.T, om *OrderedMap[interface{}, interface{}], ifunc VisitorFunc, values []KeyValue, reverse bool) {
 
-	iterFunc := func(t *testing.T, om *OrderedMap, iter_num int, key interface{}, value interface{}) {
+	iterFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int, key interface{}, value interface{}) {
 
 		if iter_num >= len(values) {
 			t.Error("Iteration too long")
@@ -404,7 +404,7 @@ func TestIterDeleteCurrent(t *testing.T) {
 
 	for num := range tests {
 
-		deletef := func(t *testing.T, om *OrderedMap, iter_num int,
+		deletef := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int,
 			key interface{}, value interface{}) {
 			if iter_num == num {
 				om.Delete(num)
@@ -505,7 +505,7 @@ func TestIterDeleteOneRandom(t *testing.T) {
 			}
 
 			// delete key at delete_pos
-			deleteFunc := func(t *testing.T, om *OrderedMap, iterNum int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iterNum int,
 				key interface{}, value interface{}) {
 				if iterNum == pos {
 					om.Delete(deleteKey)
@@ -539,7 +539,7 @@ func TestIterDeleteOneRandom(t *testing.T) {
 				om.Set(test.key, test.value)
 			}
 
-			deleteFunc := func(t *testing.T, om *OrderedMap, iterNum int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iterNum int,
 				key interface{}, value interface{}) {
 				if key.(int) == pos {
 					om.Delete(deleteKey)
@@ -586,7 +586,7 @@ func TestIterInsertDelete(t *testing.T) {
 				om.Set(test.key, test.value)
 			}
 
-			deleteFunc := func(t *testing.T, om *OrderedMap, iter_num int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int,
 				key interface{}, value interface{}) {
 				if iter_num == pos {
 					om.Set(pos+1000, pos+1000)
@@ -627,7 +627,7 @@ func TestIterInsertDelete(t *testing.T) {
 				om.Set(test.key, test.value)
 			}
 
-			deleteFunc := func(t *testing.T, om *OrderedMap, iter_num int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int,
 				key interface{}, value interface{}) {
 				if key.(int) == pos {
 					om.Set(pos+1000, pos+1000)
@@ -679,7 +679,7 @@ func TestIterDelteInsert(t *testing.T) {
 				om.Set(test.key, test.value)
 			}
 
-			deleteFunc := func(t *testing.T, om *OrderedMap, iter_num int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int,
 				key interface{}, value interface{}) {
 				if iter_num == pos {
 					om.Delete(deleteKey)
@@ -722,7 +722,7 @@ func TestIterDelteInsert(t *testing.T) {
 				om.Set(test.key, test.value)
 			}
 
-			deleteFunc := func(t *testing.T, om *OrderedMap, iter_num int,
+			deleteFunc := func(t *testing.T, om *OrderedMap[interface{}, interface{}], iter_num int,
 				key interface{}, value interface{}) {
 				if key.(int) == pos {
 					om.Delete(deleteKey)
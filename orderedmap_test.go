@@ -6,7 +6,7 @@ import (
 )
 
 // Test key present in OrderedMap
-func mapHasKey(t *testing.T, om *OrderedMap, key interface{}, value interface{}) bool {
+func mapHasKey(t *testing.T, om *OrderedMap[interface{}, interface{}], key interface{}, value interface{}) bool {
 
 	if v, ok := om.Get(key); v != value || !ok {
 		t.Error(fmt.Sprintf("Get(%v) -> expected %v received %v", key, value, v))
@@ -15,8 +15,17 @@ func mapHasKey(t *testing.T, om *OrderedMap, key interface{}, value interface{})
 	return true
 }
 
+// Keys returned by a MapIterator
+func iterKeys[K comparable, V any](mi *MapIterator[K, V]) []K {
+	var keys []K
+	for key, _, ok := mi.Next(); ok; key, _, ok = mi.Next() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // Test key not present in OrderedMap
-func mapNotKey(t *testing.T, om *OrderedMap, key interface{}) bool {
+func mapNotKey(t *testing.T, om *OrderedMap[interface{}, interface{}], key interface{}) bool {
 
 	if v, ok := om.Get(key); v != nil || ok {
 		t.Error(fmt.Sprintf("Get(%v) -> shouldn't have a value", key))
@@ -26,7 +35,7 @@ func mapNotKey(t *testing.T, om *OrderedMap, key interface{}) bool {
 }
 
 // Test map is empty
-func mapIsEmpty(t *testing.T, om *OrderedMap) bool {
+func mapIsEmpty(t *testing.T, om *OrderedMap[interface{}, interface{}]) bool {
 
 	if om.Len() != 0 {
 		t.Error("Map is not empty")
@@ -343,8 +352,48 @@ func TestString(t *testing.T) {
 	}
 
 	om.Set(1, 2)
-	if fmt.Sprintf("%v", om) != "OrderedMap[1:2, ]" {
+	if fmt.Sprintf("%v", om) != "OrderedMap[1:2]" {
 		t.Error("Invalid OrderedMap representation")
 	}
 
 }
+
+// Test a map with static key and value types
+func TestTypedOrderedMap(t *testing.T) {
+	om := New[string, int]()
+
+	if value, ok := om.Get("missing"); value != 0 || ok {
+		t.Error(fmt.Sprintf("Expecting 0, false -> Returned %v %v", value, ok))
+	}
+
+	om.Set("one", 1)
+	om.Set("two", 2)
+	om.Set("three", 3)
+	om.Set("one", 11)
+
+	var sum int
+	iter := om.Iter()
+	for _, value, ok := iter.Next(); ok; _, value, ok = iter.Next() {
+		sum += value
+	}
+	if sum != 16 {
+		t.Error("Expecting sum 16, returned ", sum)
+	}
+
+	om.MoveLast("one")
+	if key, value, ok := om.PopLast(); key != "one" || value != 11 || !ok {
+		t.Error(fmt.Sprintf("Expecting 'one', 11, true -> Returned %v %v %v",
+			key, value, ok))
+	}
+
+	if key, value, ok := om.PopFirst(); key != "two" || value != 2 || !ok {
+		t.Error(fmt.Sprintf("Expecting 'two', 2, true -> Returned %v %v %v",
+			key, value, ok))
+	}
+
+	om.Delete("three")
+	if key, value, ok := om.GetFirst(); key != "" || value != 0 || ok {
+		t.Error(fmt.Sprintf("Expecting '', 0, false -> Returned %v %v %v",
+			key, value, ok))
+	}
+}
@@ -0,0 +1,419 @@
+package orderedmap
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/rand/v2"
+)
+
+// PersistentMap is an immutable OrderedMap. Set, Delete and Move return a new
+// version of the map and leave the original unchanged, both versions share
+// most of their structure so each change only copies O(log n) nodes. As a
+// version can't be modified, it can be freely shared between goroutines.
+//
+// Keys are stored in a hash array mapped trie, and the order in a persistent
+// treap keyed by a sequence number assigned when a key is inserted or moved.
+// For many changes in a row use a TransientMap, from Transient, which avoids
+// most of the copies. PersistentMaps must be created with NewPersistent.
+type PersistentMap[K comparable, V any] struct {
+	seed    maphash.Seed
+	hamt    *hamtNode[K, V]
+	order   *orderNode[K, V]
+	length  int
+	minSeq  int64 // Sequence number of the first element, or less
+	nextSeq int64 // Sequence number for the next element appended
+}
+
+// A node of the order treap
+type orderNode[K comparable, V any] struct {
+	left     *orderNode[K, V]
+	right    *orderNode[K, V]
+	seq      int64
+	priority uint32
+	key      K
+	value    V
+	owner    *editToken
+}
+
+// NewPersistent creates an empty PersistentMap
+func NewPersistent[K comparable, V any]() *PersistentMap[K, V] {
+	return &PersistentMap[K, V]{seed: maphash.MakeSeed()}
+}
+
+// Len returns the number of elements in the Map
+func (pm *PersistentMap[K, V]) Len() int {
+	return pm.length
+}
+
+// Get the value of an existing key
+func (pm *PersistentMap[K, V]) Get(key K) (value V, ok bool) {
+	entry, ok := pm.hamt.get(maphash.Comparable(pm.seed, key), key)
+	return entry.value, ok
+}
+
+// GetFirst returns the key and value for the first element
+func (pm *PersistentMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	n := pm.order
+	if n == nil {
+		return
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// GetLast returns the key and value for the last element
+func (pm *PersistentMap[K, V]) GetLast() (key K, value V, ok bool) {
+	n := pm.order
+	if n == nil {
+		return
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// Set returns a version of the map with the key value set. As with
+// OrderedMap.Set an existing key keeps its position, otherwise it is
+// inserted at the end.
+func (pm *PersistentMap[K, V]) Set(key K, value V) *PersistentMap[K, V] {
+	next := *pm
+	next.set(key, value, nil)
+	return &next
+}
+
+// Delete returns a version of the map without the key, or the same map if
+// the key doesn't exist.
+func (pm *PersistentMap[K, V]) Delete(key K) *PersistentMap[K, V] {
+	next := *pm
+	if !next.delete(key, nil) {
+		return pm
+	}
+	return &next
+}
+
+// Move returns a version of the map with an existing key moved to either
+// end, or the same map if the key doesn't exist.
+func (pm *PersistentMap[K, V]) Move(key K, last bool) *PersistentMap[K, V] {
+	next := *pm
+	if !next.move(key, last, nil) {
+		return pm
+	}
+	return &next
+}
+
+// MoveLast is a shortcut to Move a key to the end of the map
+func (pm *PersistentMap[K, V]) MoveLast(key K) *PersistentMap[K, V] {
+	return pm.Move(key, true)
+}
+
+// MoveFirst is a shortcut to Move a key to the beginning of the map
+func (pm *PersistentMap[K, V]) MoveFirst(key K) *PersistentMap[K, V] {
+	return pm.Move(key, false)
+}
+
+// Transient returns a TransientMap with the same contents as the map, to
+// make many changes without creating a version for each of them.
+func (pm *PersistentMap[K, V]) Transient() *TransientMap[K, V] {
+	return &TransientMap[K, V]{pm: *pm, owner: &editToken{}}
+}
+
+// Set a key value, copying the nodes not created by owner
+func (pm *PersistentMap[K, V]) set(key K, value V, owner *editToken) {
+	hash := maphash.Comparable(pm.seed, key)
+	entry, ok := pm.hamt.get(hash, key)
+	if ok {
+		entry.value = value
+		pm.hamt, _ = hamtSet(pm.hamt, hash, entry, 0, owner)
+		pm.order = orderUpdate(pm.order, entry.seq, value, owner)
+		return
+	}
+
+	entry = hamtEntry[K, V]{key: key, value: value, seq: pm.nextSeq}
+	pm.nextSeq++
+	pm.hamt, _ = hamtSet(pm.hamt, hash, entry, 0, owner)
+	pm.order = orderInsert(pm.order, newOrderNode(entry, owner), owner)
+	pm.length++
+}
+
+// Delete a key, returns false if it doesn't exist
+func (pm *PersistentMap[K, V]) delete(key K, owner *editToken) bool {
+	hash := maphash.Comparable(pm.seed, key)
+	entry, ok := pm.hamt.get(hash, key)
+	if !ok {
+		return false
+	}
+
+	pm.hamt, _ = hamtDelete(pm.hamt, hash, key, 0, owner)
+	pm.order = orderDelete(pm.order, entry.seq, owner)
+	pm.length--
+	return true
+}
+
+// Move a key to either end with a new sequence number, returns false if it
+// doesn't exist.
+func (pm *PersistentMap[K, V]) move(key K, last bool, owner *editToken) bool {
+	hash := maphash.Comparable(pm.seed, key)
+	entry, ok := pm.hamt.get(hash, key)
+	if !ok {
+		return false
+	}
+	pm.order = orderDelete(pm.order, entry.seq, owner)
+
+	if last {
+		entry.seq = pm.nextSeq
+		pm.nextSeq++
+	} else {
+		pm.minSeq--
+		entry.seq = pm.minSeq
+	}
+	pm.hamt, _ = hamtSet(pm.hamt, hash, entry, 0, owner)
+	pm.order = orderInsert(pm.order, newOrderNode(entry, owner), owner)
+	return true
+}
+
+// PersistentIterator is an iterator over a PersistentMap version, as the
+// version can't change it is never affected by other changes to the map.
+type PersistentIterator[K comparable, V any] struct {
+	stack   []*orderNode[K, V]
+	reverse bool
+}
+
+// Iter creates a map iterator
+func (pm *PersistentMap[K, V]) Iter() *PersistentIterator[K, V] {
+	pi := &PersistentIterator[K, V]{reverse: false}
+	pi.push(pm.order)
+	return pi
+}
+
+// IterReverse creates a reverse order map iterator
+func (pm *PersistentMap[K, V]) IterReverse() *PersistentIterator[K, V] {
+	pi := &PersistentIterator[K, V]{reverse: true}
+	pi.push(pm.order)
+	return pi
+}
+
+// Push a node and its descendants up to the next one in the iteration order
+func (pi *PersistentIterator[K, V]) push(n *orderNode[K, V]) {
+	for n != nil {
+		pi.stack = append(pi.stack, n)
+		if pi.reverse {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+}
+
+// Next key:value pair
+func (pi *PersistentIterator[K, V]) Next() (key K, value V, ok bool) {
+	if len(pi.stack) == 0 {
+		return
+	}
+
+	n := pi.stack[len(pi.stack)-1]
+	pi.stack = pi.stack[:len(pi.stack)-1]
+	if pi.reverse {
+		pi.push(n.left)
+	} else {
+		pi.push(n.right)
+	}
+	return n.key, n.value, true
+}
+
+// All returns an iterator over the key:value pairs in order
+func (pm *PersistentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		pi := pm.Iter()
+		for key, value, ok := pi.Next(); ok; key, value, ok = pi.Next() {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the key:value pairs in reverse order
+func (pm *PersistentMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		pi := pm.IterReverse()
+		for key, value, ok := pi.Next(); ok; key, value, ok = pi.Next() {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the map contents: PersistentMap[a:1 b:2]
+func (pm *PersistentMap[K, V]) String() string {
+	return fmt.Sprint(pm)
+}
+
+// Format implements fmt.Formatter, see OrderedMap.Format
+func (pm *PersistentMap[K, V]) Format(f fmt.State, verb rune) {
+	formatItems(f, verb, "PersistentMap", pm.All(), pm.Len())
+}
+
+// TransientMap is a mutable builder for a PersistentMap. Its changes modify
+// in place the nodes it already copied, so a batch of changes is much faster
+// than creating a version for each one. Persistent returns the current
+// contents as a PersistentMap.
+//
+// TransientMap is not safe for concurrent use.
+type TransientMap[K comparable, V any] struct {
+	pm    PersistentMap[K, V]
+	owner *editToken
+}
+
+// Len returns the number of elements in the Map
+func (tm *TransientMap[K, V]) Len() int {
+	return tm.pm.Len()
+}
+
+// Get the value of an existing key
+func (tm *TransientMap[K, V]) Get(key K) (value V, ok bool) {
+	return tm.pm.Get(key)
+}
+
+// Set the key value, see OrderedMap.Set
+func (tm *TransientMap[K, V]) Set(key K, value V) {
+	tm.pm.set(key, value, tm.owner)
+}
+
+// Delete a key:value pair from the map.
+func (tm *TransientMap[K, V]) Delete(key K) {
+	tm.pm.delete(key, tm.owner)
+}
+
+// Move an existing key to either end of the map
+func (tm *TransientMap[K, V]) Move(key K, last bool) (ok bool) {
+	return tm.pm.move(key, last, tm.owner)
+}
+
+// MoveLast is a shortcut to Move a key to the end of the map
+func (tm *TransientMap[K, V]) MoveLast(key K) (ok bool) {
+	return tm.Move(key, true)
+}
+
+// MoveFirst is a shortcut to Move a key to the beginning of the map
+func (tm *TransientMap[K, V]) MoveFirst(key K) (ok bool) {
+	return tm.Move(key, false)
+}
+
+// Persistent returns the current contents as a PersistentMap. The transient
+// can still be used afterwards, its later changes don't affect the returned
+// map.
+func (tm *TransientMap[K, V]) Persistent() *PersistentMap[K, V] {
+	pm := tm.pm
+	tm.owner = &editToken{} // The current nodes are shared from now on
+	return &pm
+}
+
+func newOrderNode[K comparable, V any](entry hamtEntry[K, V], owner *editToken) *orderNode[K, V] {
+	return &orderNode[K, V]{
+		seq:      entry.seq,
+		priority: rand.Uint32(),
+		key:      entry.key,
+		value:    entry.value,
+		owner:    owner,
+	}
+}
+
+// Return a node that can be modified by owner, n itself if owner created it
+// or otherwise a copy.
+func (n *orderNode[K, V]) edit(owner *editToken) *orderNode[K, V] {
+	if owner != nil && n.owner == owner {
+		return n
+	}
+	c := *n
+	c.owner = owner
+	return &c
+}
+
+// The order treap is a min-heap on priority and a search tree on seq. All
+// functions return the new root of the subtree they modify.
+
+// Insert a new node whose seq isn't in the tree
+func orderInsert[K comparable, V any](t *orderNode[K, V], n *orderNode[K, V], owner *editToken) *orderNode[K, V] {
+	if t == nil {
+		return n
+	}
+	if n.priority < t.priority {
+		n.left, n.right = orderSplit(t, n.seq, owner)
+		return n
+	}
+
+	t = t.edit(owner)
+	if n.seq < t.seq {
+		t.left = orderInsert(t.left, n, owner)
+	} else {
+		t.right = orderInsert(t.right, n, owner)
+	}
+	return t
+}
+
+// Split a tree in the nodes with seq lower than seq and the rest
+func orderSplit[K comparable, V any](t *orderNode[K, V], seq int64, owner *editToken) (left *orderNode[K, V], right *orderNode[K, V]) {
+	if t == nil {
+		return nil, nil
+	}
+
+	t = t.edit(owner)
+	if t.seq < seq {
+		t.right, right = orderSplit(t.right, seq, owner)
+		return t, right
+	}
+	left, t.left = orderSplit(t.left, seq, owner)
+	return left, t
+}
+
+// Join two trees, every seq in left is lower than every seq in right
+func orderMerge[K comparable, V any](left *orderNode[K, V], right *orderNode[K, V], owner *editToken) *orderNode[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority < right.priority:
+		left = left.edit(owner)
+		left.right = orderMerge(left.right, right, owner)
+		return left
+	default:
+		right = right.edit(owner)
+		right.left = orderMerge(left, right.left, owner)
+		return right
+	}
+}
+
+// Delete the node with an existing seq
+func orderDelete[K comparable, V any](t *orderNode[K, V], seq int64, owner *editToken) *orderNode[K, V] {
+	if t.seq == seq {
+		return orderMerge(t.left, t.right, owner)
+	}
+
+	t = t.edit(owner)
+	if seq < t.seq {
+		t.left = orderDelete(t.left, seq, owner)
+	} else {
+		t.right = orderDelete(t.right, seq, owner)
+	}
+	return t
+}
+
+// Replace the value of the node with an existing seq
+func orderUpdate[K comparable, V any](t *orderNode[K, V], seq int64, value V, owner *editToken) *orderNode[K, V] {
+	t = t.edit(owner)
+	switch {
+	case seq < t.seq:
+		t.left = orderUpdate(t.left, seq, value, owner)
+	case seq > t.seq:
+		t.right = orderUpdate(t.right, seq, value, owner)
+	default:
+		t.value = value
+	}
+	return t
+}
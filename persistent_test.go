@@ -0,0 +1,216 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// Check a PersistentMap has the same contents and order as an OrderedMap
+func checkPersistent(t *testing.T, pm *PersistentMap[int, int], om *OrderedMap[int, int]) {
+	t.Helper()
+
+	if pm.Len() != om.Len() {
+		t.Fatal(fmt.Sprintf("Expecting length %v received %v", om.Len(), pm.Len()))
+	}
+	if pm.String() != strings.Replace(om.String(), "OrderedMap", "PersistentMap", 1) {
+		t.Fatal(fmt.Sprintf("Expecting %v received %v", om, pm))
+	}
+
+	var reversed []int
+	for key, value := range pm.Backward() {
+		reversed = append(reversed, key)
+		if expected, _ := om.Get(key); expected != value {
+			t.Fatal(fmt.Sprintf("Key %v expecting %v received %v", key, expected, value))
+		}
+	}
+	if fmt.Sprint(reversed) != fmt.Sprint(om.KeySliceReverse()) {
+		t.Fatal("Invalid reverse order ", reversed)
+	}
+
+	for key, value := range om.All() {
+		if v, ok := pm.Get(key); v != value || !ok {
+			t.Fatal(fmt.Sprintf("Get(%v) expecting %v received %v %v", key, value, v, ok))
+		}
+	}
+}
+
+func TestPersistentMap(t *testing.T) {
+	empty := NewPersistent[string, int]()
+	v1 := empty.Set("a", 1).Set("b", 2).Set("c", 3)
+	v2 := v1.Set("a", 10).Delete("b").MoveFirst("c")
+	v3 := v2.MoveLast("c").Set("d", 4)
+
+	tests := []struct {
+		pm       *PersistentMap[string, int]
+		expected string
+	}{
+		{empty, "PersistentMap[]"},
+		{v1, "PersistentMap[a:1 b:2 c:3]"},
+		{v2, "PersistentMap[c:3 a:10]"},
+		{v3, "PersistentMap[a:10 c:3 d:4]"},
+	}
+	for _, test := range tests {
+		if test.pm.String() != test.expected {
+			t.Error(fmt.Sprintf("Expecting %v received %v", test.expected, test.pm))
+		}
+	}
+
+	if key, value, ok := v3.GetFirst(); key != "a" || value != 10 || !ok {
+		t.Error("Invalid GetFirst ", key, value, ok)
+	}
+	if key, value, ok := v3.GetLast(); key != "d" || value != 4 || !ok {
+		t.Error("Invalid GetLast ", key, value, ok)
+	}
+	if _, _, ok := empty.GetFirst(); ok {
+		t.Error("GetFirst on empty map")
+	}
+	if _, ok := v2.Get("b"); ok {
+		t.Error("Deleted key found")
+	}
+
+	// Changes that don't modify the map return the same version
+	if v3.Delete("z") != v3 || v3.Move("z", true) != v3 {
+		t.Error("Changing a missing key created a new version")
+	}
+
+	// Iterators
+	var keys []string
+	iter := v3.IterReverse()
+	for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[d c a]" {
+		t.Error("Invalid reverse iteration ", keys)
+	}
+}
+
+func TestPersistentMapRandom(t *testing.T) {
+	pm := NewPersistent[int, int]()
+	om := New[int, int]()
+
+	type version struct {
+		pm *PersistentMap[int, int]
+		om *OrderedMap[int, int]
+	}
+	var versions []version
+
+	for i := 0; i < 2000; i++ {
+		key := rand.IntN(300)
+		switch rand.IntN(5) {
+		case 0:
+			pm = pm.Delete(key)
+			om.Delete(key)
+		case 1:
+			last := rand.IntN(2) == 0
+			pm = pm.Move(key, last)
+			om.Move(key, last)
+		default:
+			pm = pm.Set(key, i)
+			om.Set(key, i)
+		}
+
+		if i%100 == 0 {
+			versions = append(versions, version{pm, om.Clone()})
+		}
+	}
+
+	// Older versions are unchanged
+	for _, v := range versions {
+		checkPersistent(t, v.pm, v.om)
+	}
+	checkPersistent(t, pm, om)
+}
+
+func TestTransientMap(t *testing.T) {
+	base := NewPersistent[int, int]().Set(1, 1).Set(2, 2)
+
+	tm := base.Transient()
+	for i := 3; i < 1000; i++ {
+		tm.Set(i, i)
+	}
+	tm.Delete(1)
+	if !tm.MoveFirst(500) || tm.MoveLast(-1) {
+		t.Error("Invalid Move result")
+	}
+	if value, ok := tm.Get(500); value != 500 || !ok || tm.Len() != 998 {
+		t.Error("Invalid transient contents ", value, ok, tm.Len())
+	}
+
+	// Nodes created by the transient are modified in place
+	hamt, order := tm.pm.hamt, tm.pm.order
+	tm.Set(999, 999)
+	if tm.pm.hamt != hamt || tm.pm.order != order {
+		t.Error("Transient copied its own nodes")
+	}
+
+	first := tm.Persistent()
+
+	// Changes after Persistent don't modify the returned map
+	tm.Set(500, -1)
+	tm.Delete(2)
+	tm.MoveLast(3)
+	second := tm.Persistent()
+
+	om := New[int, int]()
+	om.Set(1, 1)
+	om.Set(2, 2)
+	checkPersistent(t, base, om)
+
+	om.Delete(1)
+	for i := 3; i < 1000; i++ {
+		om.Set(i, i)
+	}
+	om.MoveFirst(500)
+	checkPersistent(t, first, om)
+
+	om.Set(500, -1)
+	om.Delete(2)
+	om.MoveLast(3)
+	checkPersistent(t, second, om)
+}
+
+func TestHAMTCollisions(t *testing.T) {
+	var root *hamtNode[string, int]
+	owner := &editToken{}
+
+	// Same full hash, and hashes sharing the first levels
+	hashes := map[string]uint64{"a": 7, "b": 7, "c": 7 | 1<<40, "d": 8}
+	for key, hash := range hashes {
+		var added bool
+		root, added = hamtSet(root, hash, hamtEntry[string, int]{key: key, value: len(key)}, 0, owner)
+		if !added {
+			t.Error("Key not added ", key)
+		}
+	}
+	root, added := hamtSet(root, 7, hamtEntry[string, int]{key: "b", value: 20}, 0, nil)
+	if added {
+		t.Error("Existing key added")
+	}
+
+	for key, hash := range hashes {
+		if _, ok := root.get(hash, key); !ok {
+			t.Error("Key not found ", key)
+		}
+	}
+	if entry, _ := root.get(7, "b"); entry.value != 20 {
+		t.Error("Value not updated ", entry.value)
+	}
+	if _, ok := root.get(7, "z"); ok {
+		t.Error("Missing key found")
+	}
+
+	for _, key := range []string{"a", "c", "b", "d"} {
+		var ok bool
+		if root, ok = hamtDelete(root, hashes[key], key, 0, nil); !ok {
+			t.Error("Key not deleted ", key)
+		}
+		if _, ok := root.get(hashes[key], key); ok {
+			t.Error("Deleted key found ", key)
+		}
+	}
+	if root != nil {
+		t.Error("Empty trie not removed")
+	}
+}
@@ -0,0 +1,290 @@
+package orderedmap
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by Push when the queue is full and its policy
+	// is QueueReject.
+	ErrQueueFull = errors.New("orderedmap: queue is full")
+
+	// ErrQueueClosed is returned when pushing to a closed queue, or waiting
+	// for an item on a closed and empty queue.
+	ErrQueueClosed = errors.New("orderedmap: queue is closed")
+)
+
+// QueuePolicy selects what Push does when a Queue is full
+type QueuePolicy int
+
+const (
+	// QueueBlock waits until there is room for the new item
+	QueueBlock QueuePolicy = iota
+
+	// QueueDropOldest removes the item pushed before any other to make room
+	// for the new one.
+	QueueDropOldest
+
+	// QueueReject fails with ErrQueueFull
+	QueueReject
+)
+
+// Queue is a queue of unique keys with a value each, built on an OrderedMap.
+// Pushing a key that is already queued doesn't add it again, instead the
+// queued item is left unchanged or refreshed as set by SetDuplicatePolicy.
+// This makes it useful as a work queue keyed by job ID.
+//
+// A FIFO queue pops the oldest item first and a LIFO queue the newest.
+// Queue is safe for concurrent use by multiple producers and consumers.
+type Queue[K comparable, V any] struct {
+	mu         sync.Mutex
+	om         *OrderedMap[K, V] // Items from the oldest to the newest
+	lifo       bool
+	capacity   int
+	policy     QueuePolicy
+	duplicates MergePolicy
+	onDrop     func(key K, value V)
+	closed     bool
+
+	// Closed to wake the goroutines waiting for an item or for room, they
+	// are created by the first waiter and cleared when closed.
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+// NewFIFOQueue creates an empty queue that pops the oldest item first. It
+// holds at most capacity items, or any number of them if capacity is not
+// positive, and policy selects what Push does when it's full.
+func NewFIFOQueue[K comparable, V any](capacity int, policy QueuePolicy) *Queue[K, V] {
+	return &Queue[K, V]{
+		om:       New[K, V](),
+		capacity: capacity,
+		policy:   policy,
+	}
+}
+
+// NewLIFOQueue creates an empty queue that pops the newest item first, as a
+// stack. capacity and policy are as in NewFIFOQueue.
+func NewLIFOQueue[K comparable, V any](capacity int, policy QueuePolicy) *Queue[K, V] {
+	q := NewFIFOQueue[K, V](capacity, policy)
+	q.lifo = true
+	return q
+}
+
+// SetDuplicatePolicy selects what Push does with a key already queued:
+// MergeKeepExisting ignores the new value (the default), MergeOverwrite
+// replaces the value keeping the item position, and MergeOverwriteMove
+// replaces the value and moves the item as if it was pushed again.
+func (q *Queue[K, V]) SetDuplicatePolicy(policy MergePolicy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.duplicates = policy
+}
+
+// OnDrop sets a function called with every item removed by the
+// QueueDropOldest policy. It is called without holding the queue lock.
+func (q *Queue[K, V]) OnDrop(fn func(key K, value V)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onDrop = fn
+}
+
+// Len returns the number of queued items
+func (q *Queue[K, V]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.om.Len()
+}
+
+// Capacity returns the maximum number of queued items, not positive if the
+// queue is unbounded.
+func (q *Queue[K, V]) Capacity() int {
+	return q.capacity
+}
+
+// Contains returns true if the key is queued
+func (q *Queue[K, V]) Contains(key K) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.om.table[key]
+	return ok
+}
+
+// Push adds an item to the queue, see PushWait. With the QueueBlock policy
+// it waits for room as long as needed.
+func (q *Queue[K, V]) Push(key K, value V) (added bool, err error) {
+	return q.PushWait(context.Background(), key, value)
+}
+
+// PushWait adds an item to the queue, added is false if the key was already
+// queued and was handled by the duplicate policy. When the queue is full and
+// its policy is QueueBlock, it waits until there is room or ctx is done,
+// returning the context error.
+func (q *Queue[K, V]) PushWait(ctx context.Context, key K, value V) (added bool, err error) {
+	var dropped *node[K, V]
+
+	q.mu.Lock()
+	for {
+		if q.closed {
+			q.mu.Unlock()
+			return false, ErrQueueClosed
+		}
+		if node, ok := q.om.table[key]; ok {
+			q.pushDuplicate(node, value)
+			q.mu.Unlock()
+			return false, nil
+		}
+		if q.capacity <= 0 || q.om.Len() < q.capacity {
+			break
+		}
+
+		if q.policy == QueueReject {
+			q.mu.Unlock()
+			return false, ErrQueueFull
+		}
+		if q.policy == QueueDropOldest {
+			dropped = q.om.root.Next
+			q.om.remove(dropped, EventPop)
+			break
+		}
+
+		wait := waitChan(&q.notFull)
+		q.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		q.mu.Lock()
+	}
+
+	q.om.insert(key, value, q.om.root)
+	wake(&q.notEmpty)
+	onDrop := q.onDrop
+	q.mu.Unlock()
+
+	if dropped != nil && onDrop != nil {
+		onDrop(dropped.Key, dropped.Value)
+	}
+	return true, nil
+}
+
+// Handle a push of a key already queued
+func (q *Queue[K, V]) pushDuplicate(n *node[K, V], value V) {
+	switch q.duplicates {
+	case MergeOverwrite:
+		q.om.update(n, value)
+	case MergeOverwriteMove:
+		q.om.update(n, value)
+		q.om.move(n, q.om.root)
+	}
+}
+
+// Pop removes and returns the next item, ok is false if the queue is empty.
+// It never waits.
+func (q *Queue[K, V]) Pop() (key K, value V, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.om.Len() == 0 {
+		return
+	}
+	key, value = q.pop()
+	return key, value, true
+}
+
+// PopWait removes and returns the next item, waiting until one is pushed if
+// the queue is empty. It returns the context error if ctx is done first, or
+// ErrQueueClosed if the queue is closed and empty.
+func (q *Queue[K, V]) PopWait(ctx context.Context) (key K, value V, err error) {
+	q.mu.Lock()
+	for q.om.Len() == 0 {
+		if q.closed {
+			q.mu.Unlock()
+			return key, value, ErrQueueClosed
+		}
+
+		wait := waitChan(&q.notEmpty)
+		q.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return key, value, ctx.Err()
+		}
+		q.mu.Lock()
+	}
+
+	key, value = q.pop()
+	q.mu.Unlock()
+	return key, value, nil
+}
+
+// Remove and return the next item of a non empty queue
+func (q *Queue[K, V]) pop() (key K, value V) {
+	key, value, _ = q.om.Pop(q.lifo)
+	wake(&q.notFull)
+	return
+}
+
+// Peek returns the next item without removing it, ok is false if the queue
+// is empty.
+func (q *Queue[K, V]) Peek() (key K, value V, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.lifo {
+		return q.om.GetLast()
+	}
+	return q.om.GetFirst()
+}
+
+// Remove removes a queued item before it is popped, ok is false if the key
+// wasn't queued.
+func (q *Queue[K, V]) Remove(key K) (value V, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	node, ok := q.om.table[key]
+	if !ok {
+		return
+	}
+	q.om.remove(node, EventDelete)
+	wake(&q.notFull)
+	return node.Value, true
+}
+
+// Items returns the queued items in the order they would be popped
+func (q *Queue[K, V]) Items() []Pair[K, V] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.lifo {
+		return q.om.ItemsReverse()
+	}
+	return q.om.Items()
+}
+
+// Close stops accepting new items and wakes every waiting goroutine. The
+// items already queued can still be popped, once it is empty PopWait
+// returns ErrQueueClosed.
+func (q *Queue[K, V]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	wake(&q.notEmpty)
+	wake(&q.notFull)
+}
+
+// Return the channel closed by the next wake, creating it if needed
+func waitChan(ch *chan struct{}) <-chan struct{} {
+	if *ch == nil {
+		*ch = make(chan struct{})
+	}
+	return *ch
+}
+
+// Wake the goroutines waiting on a channel returned by waitChan
+func wake(ch *chan struct{}) {
+	if *ch != nil {
+		close(*ch)
+		*ch = nil
+	}
+}
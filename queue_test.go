@@ -0,0 +1,202 @@
+package orderedmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Pop every item of a queue and return their keys
+func drainQueue[V any](q *Queue[string, V]) (keys []string) {
+	for key, _, ok := q.Pop(); ok; key, _, ok = q.Pop() {
+		keys = append(keys, key)
+	}
+	return
+}
+
+func TestQueueOrder(t *testing.T) {
+	fifo := NewFIFOQueue[string, int](0, QueueBlock)
+	lifo := NewLIFOQueue[string, int](0, QueueBlock)
+	for i, key := range []string{"a", "b", "c", "b"} {
+		fifo.Push(key, i)
+		lifo.Push(key, i)
+	}
+
+	if key, _, _ := fifo.Peek(); key != "a" {
+		t.Error("FIFO Peek expecting a received ", key)
+	}
+	if key, _, _ := lifo.Peek(); key != "c" {
+		t.Error("LIFO Peek expecting c received ", key)
+	}
+	if keys := fmt.Sprint(drainQueue(fifo)); keys != "[a b c]" {
+		t.Error("FIFO expecting [a b c] received ", keys)
+	}
+	if keys := fmt.Sprint(drainQueue(lifo)); keys != "[c b a]" {
+		t.Error("LIFO expecting [c b a] received ", keys)
+	}
+	if _, _, ok := fifo.Pop(); ok {
+		t.Error("Pop from an empty queue")
+	}
+}
+
+func TestQueueDuplicates(t *testing.T) {
+	tests := []struct {
+		policy   MergePolicy
+		expected string
+	}{
+		{MergeKeepExisting, "[{a 1} {b 2} {c 3}]"},
+		{MergeOverwrite, "[{a 10} {b 2} {c 3}]"},
+		{MergeOverwriteMove, "[{b 2} {c 3} {a 10}]"},
+	}
+
+	for _, test := range tests {
+		q := NewFIFOQueue[string, int](3, QueueReject)
+		q.SetDuplicatePolicy(test.policy)
+		q.Push("a", 1)
+		q.Push("b", 2)
+		q.Push("c", 3)
+
+		// Duplicates don't need room
+		added, err := q.Push("a", 10)
+		if added || err != nil {
+			t.Error("Duplicate added ", added, err)
+		}
+		if items := fmt.Sprint(q.Items()); items != test.expected {
+			t.Error(fmt.Sprintf("Policy %v expecting %v received %v", test.policy, test.expected, items))
+		}
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	q := NewFIFOQueue[string, int](2, QueueReject)
+	q.Push("a", 1)
+	q.Push("b", 2)
+	if _, err := q.Push("c", 3); !errors.Is(err, ErrQueueFull) {
+		t.Error("Expecting ErrQueueFull received ", err)
+	}
+
+	// Drop oldest, in both FIFO and LIFO queues
+	for _, q := range []*Queue[string, int]{
+		NewFIFOQueue[string, int](2, QueueDropOldest),
+		NewLIFOQueue[string, int](2, QueueDropOldest),
+	} {
+		var dropped []string
+		q.OnDrop(func(key string, value int) {
+			dropped = append(dropped, key)
+		})
+		for i, key := range []string{"a", "b", "c", "d"} {
+			if added, err := q.Push(key, i); !added || err != nil {
+				t.Error("Push failed ", added, err)
+			}
+		}
+		if q.Len() != 2 || fmt.Sprint(dropped) != "[a b]" {
+			t.Error("Invalid drop ", q.Items(), dropped)
+		}
+	}
+
+	// Block until there is room
+	q = NewFIFOQueue[string, int](1, QueueBlock)
+	q.Push("a", 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PushWait(ctx, "b", 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expecting DeadlineExceeded received ", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Pop()
+	}()
+	if added, err := q.Push("b", 2); !added || err != nil {
+		t.Error("Blocked push failed ", added, err)
+	}
+	if key, _, _ := q.Peek(); key != "b" {
+		t.Error("Expecting b received ", key)
+	}
+}
+
+func TestQueuePopWait(t *testing.T) {
+	q := NewFIFOQueue[string, int](0, QueueBlock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := q.PopWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expecting DeadlineExceeded received ", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Push("a", 1)
+	}()
+	key, value, err := q.PopWait(context.Background())
+	if key != "a" || value != 1 || err != nil {
+		t.Error("Expecting a:1 received ", key, value, err)
+	}
+
+	// Removed items aren't popped
+	q.Push("b", 2)
+	q.Push("c", 3)
+	if value, ok := q.Remove("b"); !ok || value != 2 || q.Contains("b") {
+		t.Error("Remove failed ", value, ok)
+	}
+
+	// After closing the remaining items are still popped
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Close()
+	}()
+	q.PopWait(context.Background())
+	if _, _, err := q.PopWait(context.Background()); !errors.Is(err, ErrQueueClosed) {
+		t.Error("Expecting ErrQueueClosed received ", err)
+	}
+	if _, err := q.Push("d", 4); !errors.Is(err, ErrQueueClosed) {
+		t.Error("Expecting ErrQueueClosed received ", err)
+	}
+}
+
+func TestQueueConcurrent(t *testing.T) {
+	q := NewFIFOQueue[int, int](10, QueueBlock)
+	producers, consumers, items := 4, 4, 1000
+
+	var received sync.Map
+	var wg, done sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for {
+				key, _, err := q.PopWait(context.Background())
+				if err != nil {
+					return
+				}
+				if _, loaded := received.LoadOrStore(key, true); loaded {
+					t.Error("Item popped twice ", key)
+				}
+			}
+		}()
+	}
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := p; i < items; i += producers {
+				q.Push(i, i)
+			}
+		}()
+	}
+	wg.Wait()
+	q.Close()
+	done.Wait()
+
+	count := 0
+	received.Range(func(key, value any) bool {
+		count++
+		return true
+	})
+	if count != items {
+		t.Error(fmt.Sprintf("Expecting %v items received %v", items, count))
+	}
+}
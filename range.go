@@ -0,0 +1,133 @@
+package orderedmap
+
+import "iter"
+
+// IterFrom creates an iterator starting at key and continuing to the end of
+// the map. The start node is found through the hash table, so it doesn't
+// matter how far into the map the key is. If the key doesn't exist the
+// iterator is empty.
+func (om *OrderedMap[K, V]) IterFrom(key K) *MapIterator[K, V] {
+	from, ok := om.table[key]
+	if !ok {
+		return &MapIterator[K, V]{root: om.root}
+	}
+	return &MapIterator[K, V]{
+		curr:    from.Prev,
+		root:    om.root,
+		reverse: false,
+	}
+}
+
+// IterFromReverse creates a reverse order iterator starting at key and
+// continuing to the beginning of the map.
+func (om *OrderedMap[K, V]) IterFromReverse(key K) *MapIterator[K, V] {
+	from, ok := om.table[key]
+	if !ok {
+		return &MapIterator[K, V]{root: om.root}
+	}
+	return &MapIterator[K, V]{
+		curr:    from.Next,
+		root:    om.root,
+		reverse: true,
+	}
+}
+
+// IterRange creates an iterator from fromKey to toKey, both included. If
+// either key doesn't exist or toKey comes before fromKey the iterator is
+// empty. The iteration also stops if toKey is deleted before it is reached.
+//
+// As in every range of the package, SortedMap.IterRange included, the range
+// includes both ends, and the reverse versions take the key the iteration
+// starts at first.
+func (om *OrderedMap[K, V]) IterRange(fromKey K, toKey K) *MapIterator[K, V] {
+	from, okFrom := om.table[fromKey]
+	to, okTo := om.table[toKey]
+	if !okFrom || !okTo || !om.inOrder(from, to) {
+		return &MapIterator[K, V]{root: om.root}
+	}
+	return &MapIterator[K, V]{
+		curr:    from.Prev,
+		root:    om.root,
+		reverse: false,
+		past:    om.pastNode(to),
+	}
+}
+
+// IterRangeReverse creates a reverse order iterator from fromKey back to
+// toKey, both included. If either key doesn't exist or toKey comes after
+// fromKey the iterator is empty.
+func (om *OrderedMap[K, V]) IterRangeReverse(fromKey K, toKey K) *MapIterator[K, V] {
+	from, okFrom := om.table[fromKey]
+	to, okTo := om.table[toKey]
+	if !okFrom || !okTo || !om.inOrder(to, from) {
+		return &MapIterator[K, V]{root: om.root}
+	}
+	return &MapIterator[K, V]{
+		curr:    from.Next,
+		root:    om.root,
+		reverse: true,
+		past:    om.pastNode(to),
+	}
+}
+
+// Whether node a is b or comes before it. Without the index both nodes are
+// walked forward at once until one finds the other or reaches the end, so
+// the cost is bounded by the distance between them or from b to the end.
+func (om *OrderedMap[K, V]) inOrder(a *node[K, V], b *node[K, V]) bool {
+	if om.index != nil {
+		return om.index.rankOf(a) <= om.index.rankOf(b)
+	}
+	for n, m := a, b; ; n, m = n.Next, m.Next {
+		if n == b || m == om.root {
+			return true
+		}
+		if m == a || n == om.root {
+			return false
+		}
+	}
+}
+
+// Range end for an iterator whose last node is last, it is past the end
+// once last was returned or if it was deleted from the map.
+func (om *OrderedMap[K, V]) pastNode(last *node[K, V]) func(n *node[K, V]) bool {
+	returned := false
+	return func(n *node[K, V]) bool {
+		if returned || om.table[last.Key] != last {
+			return true
+		}
+		returned = n == last
+		return false
+	}
+}
+
+// AllFrom returns an iterator over the key:value pairs from key to the end
+// of the map, see IterFrom.
+func (om *OrderedMap[K, V]) AllFrom(key K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return om.IterFrom(key)
+	})
+}
+
+// BackwardFrom returns an iterator over the key:value pairs from key back to
+// the beginning of the map, see IterFromReverse.
+func (om *OrderedMap[K, V]) BackwardFrom(key K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return om.IterFromReverse(key)
+	})
+}
+
+// AllRange returns an iterator over the key:value pairs from fromKey to
+// toKey, see IterRange.
+func (om *OrderedMap[K, V]) AllRange(fromKey K, toKey K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return om.IterRange(fromKey, toKey)
+	})
+}
+
+// BackwardRange returns an iterator over the key:value pairs from fromKey
+// back to toKey, see IterRangeReverse.
+func (om *OrderedMap[K, V]) BackwardRange(fromKey K, toKey K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return om.IterRangeReverse(fromKey, toKey)
+	})
+}
@@ -0,0 +1,136 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIterFrom(t *testing.T) {
+	om := New[string, int]()
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		om.Set(key, i)
+	}
+
+	tests := []struct {
+		mi       *MapIterator[string, int]
+		expected string
+	}{
+		{om.IterFrom("a"), "[a b c d e]"},
+		{om.IterFrom("c"), "[c d e]"},
+		{om.IterFrom("e"), "[e]"},
+		{om.IterFrom("z"), "[]"},
+		{om.IterFromReverse("c"), "[c b a]"},
+		{om.IterFromReverse("a"), "[a]"},
+		{om.IterFromReverse("z"), "[]"},
+		{om.IterRange("b", "d"), "[b c d]"},
+		{om.IterRange("c", "c"), "[c]"},
+		{om.IterRange("a", "e"), "[a b c d e]"},
+		{om.IterRange("d", "b"), "[]"},
+		{om.IterRange("b", "a"), "[]"},
+		{om.IterRange("a", "z"), "[]"},
+		{om.IterRangeReverse("d", "b"), "[d c b]"},
+		{om.IterRangeReverse("e", "a"), "[e d c b a]"},
+		{om.IterRangeReverse("b", "c"), "[]"},
+		{om.IterRangeReverse("c", "c"), "[c]"},
+		{om.IterRangeReverse("z", "a"), "[]"},
+	}
+	for i, test := range tests {
+		if keys := fmt.Sprint(iterKeys(test.mi)); keys != test.expected {
+			t.Error(fmt.Sprintf("%v: Expecting %v received %v", i, test.expected, keys))
+		}
+	}
+
+	// Iterators stay finished
+	mi := om.IterRange("a", "b")
+	iterKeys(mi)
+	if _, _, ok := mi.Next(); ok {
+		t.Error("Finished iterator returned an element")
+	}
+}
+
+// Range bounds order, with and without the index
+func TestIterRangeOrder(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 6; i++ {
+		om.Set(i, i)
+	}
+	for _, indexed := range []bool{false, true} {
+		if indexed {
+			om.EnableIndex()
+		}
+		for from := 0; from < 6; from++ {
+			for to := 0; to < 6; to++ {
+				forward, backward := len(iterKeys(om.IterRange(from, to))), len(iterKeys(om.IterRangeReverse(from, to)))
+				if forward != max(0, to-from+1) || backward != max(0, from-to+1) {
+					t.Error(fmt.Sprintf("Range %v %v (index %v): received %v and %v elements", from, to, indexed, forward, backward))
+				}
+			}
+		}
+	}
+}
+
+func TestIterRangeModify(t *testing.T) {
+	om := New[int, int]()
+	for i := 0; i < 10; i++ {
+		om.Set(i, i)
+	}
+
+	// Deleting while iterating, including the range start
+	var keys []int
+	for key := range om.AllRange(2, 7) {
+		keys = append(keys, key)
+		om.Delete(key)
+	}
+	if fmt.Sprint(keys) != "[2 3 4 5 6 7]" {
+		t.Error("Invalid keys deleting while iterating ", keys)
+	}
+
+	// Deleting the range end stops the iteration
+	other := New[int, int]()
+	for i := 0; i < 10; i++ {
+		other.Set(i, i)
+	}
+	keys = nil
+	for key := range other.AllRange(0, 1) {
+		keys = append(keys, key)
+		other.Delete(1)
+	}
+	for key := range other.BackwardRange(8, 7) {
+		keys = append(keys, key)
+		other.Delete(7)
+	}
+	if fmt.Sprint(keys) != "[0 8]" {
+		t.Error("Invalid keys deleting the range end ", keys)
+	}
+
+	// Elements inserted after the range end aren't included
+	keys = nil
+	for key := range om.AllRange(0, 8) {
+		keys = append(keys, key)
+		if key == 8 {
+			om.Set(20, 20)
+		}
+	}
+	if fmt.Sprint(keys) != "[0 1 8]" {
+		t.Error("Invalid range after modifications ", keys)
+	}
+
+	keys = nil
+	for key := range om.BackwardFrom(8) {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[8 1 0]" {
+		t.Error("Invalid BackwardFrom ", keys)
+	}
+
+	keys = nil
+	for key := range om.AllFrom(8) {
+		keys = append(keys, key)
+	}
+	for key := range om.BackwardRange(9, 8) {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[8 9 20 9 8]" {
+		t.Error("Invalid AllFrom or BackwardRange ", keys)
+	}
+}
@@ -0,0 +1,51 @@
+package orderedmap
+
+import "iter"
+
+// Adapt MapIterators to range iterators, a new MapIterator is created each
+// time the sequence is ranged over.
+func iterSeq[K comparable, V any](newIter func() *MapIterator[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		mi := newIter()
+		for key, value, ok := mi.Next(); ok; key, value, ok = mi.Next() {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the key:value pairs in insertion order, for
+// use with range. The map can be modified while ranging with the same
+// limitations as Iter.
+func (om *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return iterSeq(om.Iter)
+}
+
+// Backward returns an iterator over the key:value pairs in reverse insertion
+// order, with the same limitations as IterReverse.
+func (om *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return iterSeq(om.IterReverse)
+}
+
+// Keys returns an iterator over the map keys in insertion order
+func (om *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range om.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the map values in insertion order
+func (om *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range om.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
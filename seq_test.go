@@ -0,0 +1,125 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	om := New[int, int]()
+	for k := 0; k < 5; k++ {
+		om.Set(k, k*10)
+	}
+
+	expected := 0
+	for key, value := range om.All() {
+		if key != expected || value != expected*10 {
+			t.Error(fmt.Sprintf("Expecting %v:%v received %v:%v",
+				expected, expected*10, key, value))
+		}
+		expected++
+	}
+	if expected != 5 {
+		t.Error("Expecting 5 iterations, received ", expected)
+	}
+
+	// Stop early
+	count := 0
+	for range om.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Error("Failed to stop iteration")
+	}
+
+	// Empty map
+	for key, value := range New[int, int]().All() {
+		t.Error(fmt.Sprintf("Iterated an empty map %v:%v", key, value))
+	}
+}
+
+func TestBackward(t *testing.T) {
+	om := New[int, int]()
+	for k := 0; k < 5; k++ {
+		om.Set(k, k*10)
+	}
+
+	expected := 4
+	for key, value := range om.Backward() {
+		if key != expected || value != expected*10 {
+			t.Error(fmt.Sprintf("Expecting %v:%v received %v:%v",
+				expected, expected*10, key, value))
+		}
+		expected--
+	}
+	if expected != -1 {
+		t.Error("Backward didn't iterate all keys")
+	}
+}
+
+func TestKeysValues(t *testing.T) {
+	om := New[string, int]()
+	om.Set("one", 1)
+	om.Set("two", 2)
+	om.Set("three", 3)
+
+	var keys []string
+	for key := range om.Keys() {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[one two three]" {
+		t.Error("Keys returned ", keys)
+	}
+
+	var values []int
+	for value := range om.Values() {
+		values = append(values, value)
+	}
+	if fmt.Sprint(values) != "[1 2 3]" {
+		t.Error("Values returned ", values)
+	}
+}
+
+// Delete the current key and insert new keys while ranging
+func TestAllModify(t *testing.T) {
+	om := New[int, int]()
+	for k := 0; k < 5; k++ {
+		om.Set(k, k)
+	}
+
+	// Delete current key
+	visited := 0
+	for key := range om.Keys() {
+		if key%2 == 0 {
+			om.Delete(key)
+		}
+		visited++
+	}
+	if visited != 5 || om.Len() != 2 {
+		t.Error("Failed deleting while ranging")
+	}
+
+	// Inserted keys are visited by All but not by Backward
+	visited = 0
+	for key := range om.Backward() {
+		om.Set(key+100, key)
+		visited++
+	}
+	if visited != 2 || om.Len() != 4 {
+		t.Error("Backward visited inserted keys")
+	}
+
+	visited = 0
+	for key := range om.All() {
+		if key < 100 {
+			om.Set(key+1000, key)
+		}
+		visited++
+	}
+	if visited != 6 || om.Len() != 6 {
+		t.Error("All didn't visit inserted keys")
+	}
+}
@@ -0,0 +1,100 @@
+package orderedmap
+
+// Slice accessors for the map contents, unlike Keys and Values which return
+// iterators these copy the contents when called.
+
+// KeySlice returns the map keys in order
+func (om *OrderedMap[K, V]) KeySlice() []K {
+	return om.AppendKeys(make([]K, 0, om.Len()))
+}
+
+// KeySliceReverse returns the map keys in reverse order
+func (om *OrderedMap[K, V]) KeySliceReverse() []K {
+	return om.AppendKeysReverse(make([]K, 0, om.Len()))
+}
+
+// ValueSlice returns the map values in order
+func (om *OrderedMap[K, V]) ValueSlice() []V {
+	return om.AppendValues(make([]V, 0, om.Len()))
+}
+
+// ValueSliceReverse returns the map values in reverse order
+func (om *OrderedMap[K, V]) ValueSliceReverse() []V {
+	return om.AppendValuesReverse(make([]V, 0, om.Len()))
+}
+
+// Items returns the map key:value pairs in order
+func (om *OrderedMap[K, V]) Items() []Pair[K, V] {
+	return om.AppendItems(make([]Pair[K, V], 0, om.Len()))
+}
+
+// ItemsReverse returns the map key:value pairs in reverse order
+func (om *OrderedMap[K, V]) ItemsReverse() []Pair[K, V] {
+	return om.AppendItemsReverse(make([]Pair[K, V], 0, om.Len()))
+}
+
+// AppendKeys appends the map keys in order to dst and returns the extended
+// slice, it doesn't allocate if dst has enough capacity.
+func (om *OrderedMap[K, V]) AppendKeys(dst []K) []K {
+	for n := om.first(); n != om.root; n = n.Next {
+		dst = append(dst, n.Key)
+	}
+	return dst
+}
+
+// AppendKeysReverse appends the map keys in reverse order to dst
+func (om *OrderedMap[K, V]) AppendKeysReverse(dst []K) []K {
+	for n := om.last(); n != om.root; n = n.Prev {
+		dst = append(dst, n.Key)
+	}
+	return dst
+}
+
+// AppendValues appends the map values in order to dst
+func (om *OrderedMap[K, V]) AppendValues(dst []V) []V {
+	for n := om.first(); n != om.root; n = n.Next {
+		dst = append(dst, n.Value)
+	}
+	return dst
+}
+
+// AppendValuesReverse appends the map values in reverse order to dst
+func (om *OrderedMap[K, V]) AppendValuesReverse(dst []V) []V {
+	for n := om.last(); n != om.root; n = n.Prev {
+		dst = append(dst, n.Value)
+	}
+	return dst
+}
+
+// AppendItems appends the map key:value pairs in order to dst
+func (om *OrderedMap[K, V]) AppendItems(dst []Pair[K, V]) []Pair[K, V] {
+	for n := om.first(); n != om.root; n = n.Next {
+		dst = append(dst, Pair[K, V]{Key: n.Key, Value: n.Value})
+	}
+	return dst
+}
+
+// AppendItemsReverse appends the map key:value pairs in reverse order to dst
+func (om *OrderedMap[K, V]) AppendItemsReverse(dst []Pair[K, V]) []Pair[K, V] {
+	for n := om.last(); n != om.root; n = n.Prev {
+		dst = append(dst, Pair[K, V]{Key: n.Key, Value: n.Value})
+	}
+	return dst
+}
+
+// First list node, or the sentinel if the map is empty. Zero value maps
+// don't have a sentinel yet, both are nil.
+func (om *OrderedMap[K, V]) first() *node[K, V] {
+	if om.root == nil {
+		return nil
+	}
+	return om.root.Next
+}
+
+// Last list node, or the sentinel if the map is empty
+func (om *OrderedMap[K, V]) last() *node[K, V] {
+	if om.root == nil {
+		return nil
+	}
+	return om.root.Prev
+}
@@ -0,0 +1,50 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSlices(t *testing.T) {
+	om := New[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	tests := []struct {
+		result   interface{}
+		expected string
+	}{
+		{om.KeySlice(), "[a b c]"},
+		{om.KeySliceReverse(), "[c b a]"},
+		{om.ValueSlice(), "[1 2 3]"},
+		{om.ValueSliceReverse(), "[3 2 1]"},
+		{om.Items(), "[{a 1} {b 2} {c 3}]"},
+		{om.ItemsReverse(), "[{c 3} {b 2} {a 1}]"},
+		{om.AppendKeys([]string{"x"}), "[x a b c]"},
+		{om.AppendKeysReverse(nil), "[c b a]"},
+		{om.AppendValues([]int{0}), "[0 1 2 3]"},
+		{om.AppendValuesReverse(nil), "[3 2 1]"},
+		{om.AppendItems(nil), "[{a 1} {b 2} {c 3}]"},
+		{om.AppendItemsReverse(nil), "[{c 3} {b 2} {a 1}]"},
+		{New[string, int]().KeySlice(), "[]"},
+		{(&OrderedMap[string, int]{}).Items(), "[]"},
+	}
+	for i, test := range tests {
+		if result := fmt.Sprint(test.result); result != test.expected {
+			t.Error(fmt.Sprintf("%v: Expecting %v received %v", i, test.expected, result))
+		}
+	}
+}
+
+func TestAppendKeysNoAlloc(t *testing.T) {
+	om := newIntMap(100)
+	keys := make([]int, 0, 100)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		keys = om.AppendKeys(keys[:0])
+	})
+	if allocs != 0 || len(keys) != 100 {
+		t.Error("AppendKeys allocated ", allocs)
+	}
+}
@@ -0,0 +1,76 @@
+package orderedmap
+
+import "slices"
+
+// SortFunc reorders the map in place using a less function over key:value
+// pairs. The sort is stable, so pairs that are neither less nor greater than
+// each other keep their relative order. Elements are relinked rather than
+// reallocated, and iterators in progress continue from the position their
+// current element has in the new order.
+func (om *OrderedMap[K, V]) SortFunc(less func(k1 K, v1 V, k2 K, v2 V) bool) {
+	before := om.resetStart()
+
+	nodes := make([]*node[K, V], 0, om.Len())
+	for n := om.root.Next; n != om.root; n = n.Next {
+		nodes = append(nodes, n)
+	}
+
+	slices.SortStableFunc(nodes, func(a, b *node[K, V]) int {
+		switch {
+		case less(a.Key, a.Value, b.Key, b.Value):
+			return -1
+		case less(b.Key, b.Value, a.Key, a.Value):
+			return 1
+		}
+		return 0
+	})
+
+	prev := om.root
+	for _, n := range nodes {
+		prev.Next, n.Prev = n, prev
+		prev = n
+	}
+	prev.Next, om.root.Prev = om.root, prev
+
+	om.reindex()
+	om.resetEnd(before)
+}
+
+// SortByKey reorders the map in place by key, see SortFunc
+func (om *OrderedMap[K, V]) SortByKey(less func(a, b K) bool) {
+	om.SortFunc(func(k1 K, v1 V, k2 K, v2 V) bool {
+		return less(k1, k2)
+	})
+}
+
+// SortByValue reorders the map in place by value, see SortFunc
+func (om *OrderedMap[K, V]) SortByValue(less func(a, b V) bool) {
+	om.SortFunc(func(k1 K, v1 V, k2 K, v2 V) bool {
+		return less(v1, v2)
+	})
+}
+
+// ReverseInPlace reverses the order of the map. As with SortFunc iterators
+// in progress continue from their current element in the new order, so a
+// forward iterator will visit again the elements it already returned.
+func (om *OrderedMap[K, V]) ReverseInPlace() {
+	before := om.resetStart()
+
+	n := om.root
+	for {
+		n.Next, n.Prev = n.Prev, n.Next
+		if n = n.Prev; n == om.root {
+			break
+		}
+	}
+
+	om.reindex()
+	om.resetEnd(before)
+}
+
+// Rebuild the position index after the list was reordered
+func (om *OrderedMap[K, V]) reindex() {
+	if om.index != nil {
+		om.index = newRankIndex(om.root)
+	}
+}
@@ -0,0 +1,110 @@
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSortFunc(t *testing.T) {
+	om := New[string, int]()
+	om.Set("carol", 70)
+	om.Set("alice", 90)
+	om.Set("bob", 70)
+	om.Set("dave", 80)
+
+	// Leaderboard by score, ties keep insertion order
+	om.SortFunc(func(k1 string, v1 int, k2 string, v2 int) bool {
+		return v1 > v2
+	})
+	if fmt.Sprint(iterKeys(om.Iter())) != "[alice dave carol bob]" {
+		t.Error("Invalid sort order ", iterKeys(om.Iter()))
+	}
+
+	var reversed []string
+	for key := range om.Backward() {
+		reversed = append(reversed, key)
+	}
+	if fmt.Sprint(reversed) != "[bob carol dave alice]" {
+		t.Error("Broken reverse links ", reversed)
+	}
+	if key, _, _ := om.GetLast(); key != "bob" {
+		t.Error("GetLast after sorting returned ", key)
+	}
+
+	// New keys are still appended at the end
+	om.Set("eve", 100)
+	if key, _, _ := om.GetLast(); key != "eve" {
+		t.Error("Set after sorting didn't append")
+	}
+
+	New[int, int]().SortByKey(func(a, b int) bool { return a < b })
+}
+
+func TestSortByKeyValue(t *testing.T) {
+	om := New[string, int]()
+	om.Set("b", 1)
+	om.Set("c", 3)
+	om.Set("a", 2)
+
+	om.SortByKey(func(a, b string) bool { return a < b })
+	if fmt.Sprint(iterKeys(om.Iter())) != "[a b c]" {
+		t.Error("Invalid key order ", iterKeys(om.Iter()))
+	}
+
+	om.SortByValue(func(a, b int) bool { return a > b })
+	if fmt.Sprint(iterKeys(om.Iter())) != "[c a b]" {
+		t.Error("Invalid value order ", iterKeys(om.Iter()))
+	}
+}
+
+func TestReverseInPlace(t *testing.T) {
+	om := New[int, int]()
+	om.ReverseInPlace()
+	if om.Len() != 0 {
+		t.Error("Reversing an empty map added keys")
+	}
+
+	for k := 0; k < 5; k++ {
+		om.Set(k, k)
+	}
+	om.ReverseInPlace()
+	if fmt.Sprint(iterKeys(om.Iter())) != "[4 3 2 1 0]" {
+		t.Error("Invalid reversed order ", iterKeys(om.Iter()))
+	}
+	om.Set(5, 5)
+	om.MoveFirst(0)
+	if fmt.Sprint(iterKeys(om.Iter())) != "[0 4 3 2 1 5]" {
+		t.Error("Invalid order after reversing ", iterKeys(om.Iter()))
+	}
+}
+
+// Iterators and the position index keep working after sorting
+func TestSortIterIndex(t *testing.T) {
+	om := New[int, int]()
+	om.EnableIndex()
+	for _, key := range []int{3, 1, 4, 0, 2} {
+		om.Set(key, key)
+	}
+
+	iter := om.Iter()
+	iter.Next() // 3
+	om.SortByKey(func(a, b int) bool { return a < b })
+
+	var rest []int
+	for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+		rest = append(rest, key)
+	}
+	if fmt.Sprint(rest) != "[4]" {
+		t.Error("Iterator didn't continue from its position ", rest)
+	}
+
+	om.ReverseInPlace()
+	for i := 0; i < 5; i++ {
+		if key, _, _ := om.GetAt(i); key != 4-i {
+			t.Error(fmt.Sprintf("GetAt(%v) expecting %v received %v", i, 4-i, key))
+		}
+		if index, _ := om.IndexOf(i); index != 4-i {
+			t.Error(fmt.Sprintf("IndexOf(%v) expecting %v received %v", i, 4-i, index))
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package orderedmap
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+)
+
+// Maximum number of skiplist levels, enough for 4^32 elements
+const skipMaxLevel = 32
+
+// SortedMap is a map that iterates in key order instead of insertion order,
+// as defined by a less function. Elements are kept in a skiplist whose first
+// level is the same linked list used by OrderedMap, so it offers the same
+// iterators, and in a hash table for O(1) lookups.
+//
+// The less function must be a strict weak ordering, and keys that are
+// neither less nor greater than each other must be equal (==).
+type SortedMap[K comparable, V any] struct {
+	table map[K]*skipNode[K, V]
+	root  *node[K, V] // Sentinel of the level 0 list
+	head  skipNode[K, V]
+	level int
+	less  func(a, b K) bool
+}
+
+// An element of the skiplist
+type skipNode[K comparable, V any] struct {
+	elem *node[K, V]
+	next []*skipNode[K, V]
+}
+
+// NewSortedMap creates an empty SortedMap ordered by the natural order of
+// its keys.
+func NewSortedMap[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedMapFunc[K, V](cmp.Less[K])
+}
+
+// NewSortedMapFunc creates an empty SortedMap ordered by a less function
+func NewSortedMapFunc[K comparable, V any](less func(a, b K) bool) *SortedMap[K, V] {
+	var key K
+	var value V
+	root := newNode[K, V](key, value, nil, nil) // sentinel Node
+	root.Next, root.Prev = root, root
+
+	return &SortedMap[K, V]{
+		table: make(map[K]*skipNode[K, V]),
+		root:  root,
+		head:  skipNode[K, V]{elem: root, next: make([]*skipNode[K, V], skipMaxLevel)},
+		level: 1,
+		less:  less,
+	}
+}
+
+// Len returns the number of elements in the Map
+func (sm *SortedMap[K, V]) Len() int {
+	return len(sm.table)
+}
+
+// Find the last skiplist node with a key less than key on every level, they
+// are stored in path if not nil. Returns the level 0 node.
+func (sm *SortedMap[K, V]) search(key K, path []*skipNode[K, V]) *skipNode[K, V] {
+	x := &sm.head
+	for i := sm.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && sm.less(x.next[i].elem.Key, key) {
+			x = x.next[i]
+		}
+		if path != nil {
+			path[i] = x
+		}
+	}
+	return x
+}
+
+func randomSkipLevel() int {
+	level := 1
+	for level < skipMaxLevel && rand.Uint32()&3 == 0 {
+		level++
+	}
+	return level
+}
+
+// Set the key value, inserting it in key order if it is new
+func (sm *SortedMap[K, V]) Set(key K, value V) {
+	if sn, ok := sm.table[key]; ok {
+		sn.elem.Value = value
+		return
+	}
+
+	var path [skipMaxLevel]*skipNode[K, V]
+	prev := sm.search(key, path[:])
+
+	level := randomSkipLevel()
+	for ; sm.level < level; sm.level++ {
+		path[sm.level] = &sm.head
+	}
+
+	sn := &skipNode[K, V]{
+		elem: newNode[K, V](key, value, prev.elem.Next, prev.elem),
+		next: make([]*skipNode[K, V], level),
+	}
+	for i := 0; i < level; i++ {
+		sn.next[i] = path[i].next[i]
+		path[i].next[i] = sn
+	}
+
+	// Level 0 list
+	sn.elem.Next.Prev = sn.elem
+	sn.elem.Prev.Next = sn.elem
+	sm.table[key] = sn
+}
+
+// Get the value of an existing key, leaving the map unchanged
+func (sm *SortedMap[K, V]) Get(key K) (value V, ok bool) {
+	if sn, isOk := sm.table[key]; isOk {
+		value, ok = sn.elem.Value, true
+	}
+	return
+}
+
+// GetFirst returns the key and value of the smallest key
+func (sm *SortedMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	if len(sm.table) != 0 {
+		node := sm.root.Next
+		key, value, ok = node.Key, node.Value, true
+	}
+	return
+}
+
+// GetLast returns the key and value of the largest key
+func (sm *SortedMap[K, V]) GetLast() (key K, value V, ok bool) {
+	if len(sm.table) != 0 {
+		node := sm.root.Prev
+		key, value, ok = node.Key, node.Value, true
+	}
+	return
+}
+
+// Delete a key:value pair from the map.
+func (sm *SortedMap[K, V]) Delete(key K) {
+	sn, ok := sm.table[key]
+	if !ok {
+		return
+	}
+
+	var path [skipMaxLevel]*skipNode[K, V]
+	sm.search(key, path[:])
+	for i := range sn.next {
+		if path[i].next[i] == sn {
+			path[i].next[i] = sn.next[i]
+		}
+	}
+	for sm.level > 1 && sm.head.next[sm.level-1] == nil {
+		sm.level--
+	}
+
+	// Level 0 list, the node pointers are kept for iterators
+	sn.elem.Next.Prev = sn.elem.Prev
+	sn.elem.Prev.Next = sn.elem.Next
+	delete(sm.table, key)
+}
+
+// Pop and return key:value for the largest or smallest key
+func (sm *SortedMap[K, V]) Pop(last bool) (key K, value V, ok bool) {
+	if last {
+		key, value, ok = sm.GetLast()
+	} else {
+		key, value, ok = sm.GetFirst()
+	}
+
+	if ok {
+		sm.Delete(key)
+	}
+	return
+}
+
+// PopLast is a shortcut to Pop the largest key
+func (sm *SortedMap[K, V]) PopLast() (key K, value V, ok bool) {
+	return sm.Pop(true)
+}
+
+// PopFirst is a shortcut to Pop the smallest key
+func (sm *SortedMap[K, V]) PopFirst() (key K, value V, ok bool) {
+	return sm.Pop(false)
+}
+
+// Floor returns the largest key less than or equal to key
+func (sm *SortedMap[K, V]) Floor(key K) (floorKey K, value V, ok bool) {
+	prev := sm.search(key, nil)
+	if next := prev.next[0]; next != nil && !sm.less(key, next.elem.Key) {
+		return next.elem.Key, next.elem.Value, true
+	}
+	if prev != &sm.head {
+		return prev.elem.Key, prev.elem.Value, true
+	}
+	return
+}
+
+// Ceiling returns the smallest key greater than or equal to key
+func (sm *SortedMap[K, V]) Ceiling(key K) (ceilingKey K, value V, ok bool) {
+	if next := sm.search(key, nil).next[0]; next != nil {
+		return next.elem.Key, next.elem.Value, true
+	}
+	return
+}
+
+// First list node with a key greater than or equal to key, or the sentinel
+func (sm *SortedMap[K, V]) ceilingNode(key K) *node[K, V] {
+	return sm.search(key, nil).elem.Next
+}
+
+// Iter creates an iterator in ascending key order
+func (sm *SortedMap[K, V]) Iter() *MapIterator[K, V] {
+	return &MapIterator[K, V]{
+		curr:    sm.root,
+		root:    sm.root,
+		reverse: false,
+	}
+}
+
+// IterReverse creates an iterator in descending key order
+func (sm *SortedMap[K, V]) IterReverse() *MapIterator[K, V] {
+	return &MapIterator[K, V]{
+		curr:    sm.root,
+		root:    sm.root,
+		reverse: true,
+	}
+}
+
+// IterRange creates an iterator over the keys from from to to, both
+// included, in ascending order. The iterator is empty if to is less than from.
+// The range end is checked by key, so keys inserted or deleted while
+// iterating are handled as by Iter.
+func (sm *SortedMap[K, V]) IterRange(from K, to K) *MapIterator[K, V] {
+	if sm.less(to, from) {
+		return &MapIterator[K, V]{root: sm.root}
+	}
+	return &MapIterator[K, V]{
+		curr:    sm.ceilingNode(from).Prev,
+		root:    sm.root,
+		reverse: false,
+		past: func(n *node[K, V]) bool {
+			return sm.less(to, n.Key)
+		},
+	}
+}
+
+// IterRangeReverse creates an iterator over the keys from from back to to,
+// both included, in descending order. The iterator is empty if from is less
+// than to.
+func (sm *SortedMap[K, V]) IterRangeReverse(from K, to K) *MapIterator[K, V] {
+	if sm.less(from, to) {
+		return &MapIterator[K, V]{root: sm.root}
+	}
+
+	// Start after the last key not greater than from
+	start := sm.ceilingNode(from)
+	if start != sm.root && !sm.less(from, start.Key) {
+		start = start.Next
+	}
+	return &MapIterator[K, V]{
+		curr:    start,
+		root:    sm.root,
+		reverse: true,
+		past: func(n *node[K, V]) bool {
+			return sm.less(n.Key, to)
+		},
+	}
+}
+
+// All returns an iterator over the key:value pairs in ascending key order
+func (sm *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return iterSeq(sm.Iter)
+}
+
+// Backward returns an iterator over the key:value pairs in descending key
+// order.
+func (sm *SortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return iterSeq(sm.IterReverse)
+}
+
+// AllRange returns an iterator over the key:value pairs from from to to in
+// ascending key order, see IterRange.
+func (sm *SortedMap[K, V]) AllRange(from K, to K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return sm.IterRange(from, to)
+	})
+}
+
+// BackwardRange returns an iterator over the key:value pairs from from back
+// to to in descending key order, see IterRangeReverse.
+func (sm *SortedMap[K, V]) BackwardRange(from K, to K) iter.Seq2[K, V] {
+	return iterSeq(func() *MapIterator[K, V] {
+		return sm.IterRangeReverse(from, to)
+	})
+}
+
+// Keys returns an iterator over the map keys in ascending order
+func (sm *SortedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range sm.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the map values in ascending key order
+func (sm *SortedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range sm.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the map contents in the same format as a built-in map,
+// prefixed by the type name: SortedMap[a:1 b:2]
+func (sm *SortedMap[K, V]) String() string {
+	return fmt.Sprint(sm)
+}
+
+// Format implements fmt.Formatter, the keys and values are formatted with the
+// same verb and flags as the map, as fmt does for built-in maps.
+func (sm *SortedMap[K, V]) Format(f fmt.State, verb rune) {
+	formatItems(f, verb, "SortedMap", sm.All(), sm.Len())
+}
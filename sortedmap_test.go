@@ -0,0 +1,204 @@
+package orderedmap
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSortedMap(t *testing.T) {
+	sm := NewSortedMap[int, string]()
+	for _, key := range []int{5, 1, 9, 3, 7} {
+		sm.Set(key, fmt.Sprint(key))
+	}
+	sm.Set(3, "three")
+
+	if fmt.Sprint(iterKeys(sm.Iter())) != "[1 3 5 7 9]" {
+		t.Error("Keys out of order ", iterKeys(sm.Iter()))
+	}
+	if value, ok := sm.Get(3); value != "three" || !ok {
+		t.Error(fmt.Sprintf("Expecting three, true -> Returned %v %v", value, ok))
+	}
+	if key, _, ok := sm.GetFirst(); key != 1 || !ok {
+		t.Error("GetFirst didn't return the smallest key")
+	}
+	if key, _, ok := sm.GetLast(); key != 9 || !ok {
+		t.Error("GetLast didn't return the largest key")
+	}
+
+	if key, _, ok := sm.PopFirst(); key != 1 || !ok {
+		t.Error("PopFirst didn't pop the smallest key")
+	}
+	if key, _, ok := sm.PopLast(); key != 9 || !ok {
+		t.Error("PopLast didn't pop the largest key")
+	}
+	sm.Delete(5)
+	sm.Delete(100)
+	if fmt.Sprint(iterKeys(sm.Iter())) != "[3 7]" || sm.Len() != 2 {
+		t.Error("Invalid keys after deleting ", iterKeys(sm.Iter()))
+	}
+
+	var reversed []int
+	iter := sm.IterReverse()
+	for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+		reversed = append(reversed, key)
+	}
+	if fmt.Sprint(reversed) != "[7 3]" {
+		t.Error("Invalid reverse iteration ", reversed)
+	}
+
+	empty := NewSortedMap[int, int]()
+	if _, _, ok := empty.PopFirst(); ok {
+		t.Error("Popped a key from an empty map")
+	}
+}
+
+func TestSortedMapFunc(t *testing.T) {
+	// Case insensitive descending order
+	sm := NewSortedMapFunc[string, int](func(a, b string) bool {
+		return strings.ToLower(a) > strings.ToLower(b)
+	})
+	for i, key := range []string{"b", "C", "a", "d"} {
+		sm.Set(key, i)
+	}
+	if fmt.Sprint(iterKeys(sm.Iter())) != "[d C b a]" {
+		t.Error("Keys out of order ", iterKeys(sm.Iter()))
+	}
+}
+
+func TestSortedMapFloorCeiling(t *testing.T) {
+	sm := NewSortedMap[int, int]()
+	for _, key := range []int{10, 20, 30} {
+		sm.Set(key, key)
+	}
+
+	tests := []struct {
+		key             int
+		floor, ceiling  int
+		floorOk, ceilOk bool
+	}{
+		{5, 0, 10, false, true},
+		{10, 10, 10, true, true},
+		{15, 10, 20, true, true},
+		{30, 30, 30, true, true},
+		{35, 30, 0, true, false},
+	}
+	for _, test := range tests {
+		if key, _, ok := sm.Floor(test.key); key != test.floor || ok != test.floorOk {
+			t.Error(fmt.Sprintf("Floor(%v) expecting %v %v received %v %v",
+				test.key, test.floor, test.floorOk, key, ok))
+		}
+		if key, _, ok := sm.Ceiling(test.key); key != test.ceiling || ok != test.ceilOk {
+			t.Error(fmt.Sprintf("Ceiling(%v) expecting %v %v received %v %v",
+				test.key, test.ceiling, test.ceilOk, key, ok))
+		}
+	}
+}
+
+func TestSortedMapRange(t *testing.T) {
+	sm := NewSortedMap[int, int]()
+	for key := 0; key < 100; key += 10 {
+		sm.Set(key, key)
+	}
+
+	tests := []struct {
+		from, to int
+		keys     string
+	}{
+		{20, 50, "[20 30 40 50]"},
+		{15, 51, "[20 30 40 50]"},
+		{-10, 15, "[0 10]"},
+		{85, 200, "[90]"},
+		{41, 49, "[]"},
+		{50, 50, "[50]"},
+		{55, 55, "[]"},
+		{60, 20, "[]"},
+	}
+	for _, test := range tests {
+		var keys, reversed []int
+		for key := range sm.AllRange(test.from, test.to) {
+			keys = append(keys, key)
+		}
+
+		// Reverse ranges take the bounds in iteration order
+		for key := range sm.BackwardRange(test.to, test.from) {
+			reversed = append([]int{key}, reversed...)
+		}
+		if fmt.Sprint(keys) != test.keys || fmt.Sprint(reversed) != test.keys {
+			t.Error(fmt.Sprintf("Range [%v, %v] expecting %v received %v and %v",
+				test.from, test.to, test.keys, keys, reversed))
+		}
+	}
+}
+
+// Deleting the current key while iterating
+func TestSortedMapIterDelete(t *testing.T) {
+	sm := NewSortedMap[int, int]()
+	for key := 0; key < 10; key++ {
+		sm.Set(key, key)
+	}
+	for key := range sm.Keys() {
+		if key%2 == 0 {
+			sm.Delete(key)
+		}
+	}
+	if fmt.Sprint(iterKeys(sm.Iter())) != "[1 3 5 7 9]" {
+		t.Error("Invalid keys after deleting ", iterKeys(sm.Iter()))
+	}
+}
+
+// Deleting the last key of a range while iterating it
+func TestSortedMapRangeDelete(t *testing.T) {
+	sm := NewSortedMap[int, int]()
+	for key := 0; key < 10; key++ {
+		sm.Set(key, key)
+	}
+
+	var keys []int
+	for key := range sm.AllRange(2, 4) {
+		keys = append(keys, key)
+		sm.Delete(4)
+	}
+	for key := range sm.BackwardRange(8, 6) {
+		keys = append(keys, key)
+		sm.Delete(6)
+	}
+	if fmt.Sprint(keys) != "[2 3 8 7]" {
+		t.Error("Invalid keys deleting the range end ", keys)
+	}
+}
+
+// Compare against a sorted slice while applying random operations
+func TestSortedMapRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sm := NewSortedMap[int, int]()
+	model := make(map[int]int)
+
+	for step := 0; step < 5000; step++ {
+		key := rnd.Intn(500)
+		if rnd.Intn(3) == 0 {
+			sm.Delete(key)
+			delete(model, key)
+		} else {
+			sm.Set(key, step)
+			model[key] = step
+		}
+	}
+
+	var keys []int
+	for key := range model {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	if fmt.Sprint(iterKeys(sm.Iter())) != fmt.Sprint(keys) {
+		t.Fatal("Keys don't match the model")
+	}
+	for _, key := range keys {
+		if value, _ := sm.Get(key); value != model[key] {
+			t.Error(fmt.Sprintf("Get(%v) expecting %v received %v", key, model[key], value))
+		}
+	}
+}
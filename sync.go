@@ -0,0 +1,297 @@
+package orderedmap
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncOrderedMap is an OrderedMap safe for concurrent use by multiple
+// goroutines. Iterators work over a snapshot of the map taken when they are
+// created, so they are never affected by concurrent writers.
+type SyncOrderedMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	om *OrderedMap[K, V]
+}
+
+// NewSync creates an empty SyncOrderedMap
+func NewSync[K comparable, V any]() *SyncOrderedMap[K, V] {
+	return &SyncOrderedMap[K, V]{om: New[K, V]()}
+}
+
+// Len returns the number of elements in the Map
+func (sm *SyncOrderedMap[K, V]) Len() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.Len()
+}
+
+// Set the key value, see OrderedMap.Set
+func (sm *SyncOrderedMap[K, V]) Set(key K, value V) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Set(key, value)
+}
+
+// Get the value of an existing key
+func (sm *SyncOrderedMap[K, V]) Get(key K) (value V, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.Get(key)
+}
+
+// GetFirst returns the key and value for the first element
+func (sm *SyncOrderedMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.GetFirst()
+}
+
+// GetLast returns the key and value for the last element
+func (sm *SyncOrderedMap[K, V]) GetLast() (key K, value V, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.GetLast()
+}
+
+// GetOrSet returns the existing value for the key if present, otherwise it
+// sets and returns the given value. loaded is true if the value was already
+// present.
+func (sm *SyncOrderedMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if actual, loaded = sm.om.Get(key); loaded {
+		return actual, true
+	}
+	sm.om.Set(key, value)
+	return value, false
+}
+
+// Update atomically replaces the value of a key with the result of fn, which
+// receives the current value and whether the key was present. If fn returns
+// keep false the key is deleted (or not inserted). New keys are inserted at
+// the end, existing keys keep their position. Returns the resulting value and
+// whether the key is present.
+//
+// fn is called with the map locked, so it must not call any of its methods.
+func (sm *SyncOrderedMap[K, V]) Update(key K, fn func(value V, ok bool) (newValue V, keep bool)) (value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	current, exists := sm.om.Get(key)
+	value, ok = fn(current, exists)
+	if ok {
+		sm.om.Set(key, value)
+	} else {
+		var zero V
+		value = zero
+		sm.om.Delete(key)
+	}
+	return
+}
+
+// SetAll sets every pair in order while holding the lock once
+func (sm *SyncOrderedMap[K, V]) SetAll(pairs []Pair[K, V]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.SetAll(pairs)
+}
+
+// Merge adds the contents of other to the map, see OrderedMap.Merge. other
+// isn't locked, so it must not be modified concurrently.
+func (sm *SyncOrderedMap[K, V]) Merge(other *OrderedMap[K, V], policy MergePolicy) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Merge(other, policy)
+}
+
+// Delete a key:value pair from the map.
+func (sm *SyncOrderedMap[K, V]) Delete(key K) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.Delete(key)
+}
+
+// Pop and return key:value for the newest or oldest element
+func (sm *SyncOrderedMap[K, V]) Pop(last bool) (key K, value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.Pop(last)
+}
+
+// PopLast is a shortcut to Pop the last element
+func (sm *SyncOrderedMap[K, V]) PopLast() (key K, value V, ok bool) {
+	return sm.Pop(true)
+}
+
+// PopFirst is a shortcut to Pop the first element
+func (sm *SyncOrderedMap[K, V]) PopFirst() (key K, value V, ok bool) {
+	return sm.Pop(false)
+}
+
+// Move an existing key to either end of the map
+func (sm *SyncOrderedMap[K, V]) Move(key K, last bool) (ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.Move(key, last)
+}
+
+// MoveLast is a shortcut to Move a key to the end of the map
+func (sm *SyncOrderedMap[K, V]) MoveLast(key K) (ok bool) {
+	return sm.Move(key, true)
+}
+
+// MoveFirst is a shortcut to Move a key to the beginning of the map
+func (sm *SyncOrderedMap[K, V]) MoveFirst(key K) (ok bool) {
+	return sm.Move(key, false)
+}
+
+// InsertBefore inserts a new key:value pair just before the mark key
+func (sm *SyncOrderedMap[K, V]) InsertBefore(mark K, key K, value V) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.InsertBefore(mark, key, value)
+}
+
+// InsertAfter inserts a new key:value pair just after the mark key
+func (sm *SyncOrderedMap[K, V]) InsertAfter(mark K, key K, value V) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.InsertAfter(mark, key, value)
+}
+
+// MoveBefore moves an existing key just before the mark key
+func (sm *SyncOrderedMap[K, V]) MoveBefore(key K, mark K) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.MoveBefore(key, mark)
+}
+
+// MoveAfter moves an existing key just after the mark key
+func (sm *SyncOrderedMap[K, V]) MoveAfter(key K, mark K) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.MoveAfter(key, mark)
+}
+
+// EnableIndex builds a position index, see OrderedMap.EnableIndex
+func (sm *SyncOrderedMap[K, V]) EnableIndex() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.om.EnableIndex()
+}
+
+// GetAt returns the key and value at position i, negative positions count
+// from the end.
+func (sm *SyncOrderedMap[K, V]) GetAt(i int) (key K, value V, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.GetAt(i)
+}
+
+// IndexOf returns the position of a key
+func (sm *SyncOrderedMap[K, V]) IndexOf(key K) (index int, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.IndexOf(key)
+}
+
+// DeleteAt removes and returns the key:value pair at position i
+func (sm *SyncOrderedMap[K, V]) DeleteAt(i int) (key K, value V, ok bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.DeleteAt(i)
+}
+
+// Snapshot returns a copy of the map contents, it can be freely used and
+// modified without any locking.
+func (sm *SyncOrderedMap[K, V]) Snapshot() *OrderedMap[K, V] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	snapshot := NewWithCapacity[K, V](sm.om.Len())
+	sm.om.CopyInto(snapshot)
+	return snapshot
+}
+
+// Iter creates an iterator over a snapshot of the map
+func (sm *SyncOrderedMap[K, V]) Iter() *MapIterator[K, V] {
+	return sm.Snapshot().Iter()
+}
+
+// IterReverse creates a reverse order iterator over a snapshot of the map
+func (sm *SyncOrderedMap[K, V]) IterReverse() *MapIterator[K, V] {
+	return sm.Snapshot().IterReverse()
+}
+
+// All returns an iterator over a snapshot of the map key:value pairs
+func (sm *SyncOrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return sm.Snapshot().All()
+}
+
+// Backward returns a reverse order iterator over a snapshot of the map
+func (sm *SyncOrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return sm.Snapshot().Backward()
+}
+
+// Keys returns an iterator over a snapshot of the map keys
+func (sm *SyncOrderedMap[K, V]) Keys() iter.Seq[K] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return slices.Values(sm.om.KeySlice())
+}
+
+// Values returns an iterator over a snapshot of the map values
+func (sm *SyncOrderedMap[K, V]) Values() iter.Seq[V] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return slices.Values(sm.om.ValueSlice())
+}
+
+// Range calls fn for each key:value pair in order while holding the read
+// lock, stopping if fn returns false. It avoids the copy made by the
+// snapshot iterators, but fn must not call any of the map methods.
+func (sm *SyncOrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for key, value := range sm.om.All() {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler, see OrderedMap.MarshalJSON
+func (sm *SyncOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	if sm == nil {
+		return []byte("null"), nil
+	}
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler while holding the lock, see
+// OrderedMap.UnmarshalJSON.
+func (sm *SyncOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.om.UnmarshalJSON(data)
+}
+
+// String returns the map contents, see OrderedMap.String
+func (sm *SyncOrderedMap[K, V]) String() string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.om.String()
+}
+
+// Format implements fmt.Formatter, see OrderedMap.Format
+func (sm *SyncOrderedMap[K, V]) Format(f fmt.State, verb rune) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	sm.om.Format(f, verb)
+}
@@ -0,0 +1,196 @@
+package orderedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSyncOrderedMap(t *testing.T) {
+	sm := NewSync[string, int]()
+	sm.Set("one", 1)
+	sm.Set("two", 2)
+	sm.Set("three", 3)
+
+	if value, ok := sm.Get("two"); value != 2 || !ok {
+		t.Error(fmt.Sprintf("Expecting 2, true -> Returned %v %v", value, ok))
+	}
+
+	sm.MoveFirst("three")
+	if key, _, _ := sm.GetFirst(); key != "three" {
+		t.Error("MoveFirst didn't move to the beginning")
+	}
+	sm.MoveLast("three")
+	if key, _, _ := sm.GetLast(); key != "three" {
+		t.Error("MoveLast didn't move to the end")
+	}
+
+	if key, value, ok := sm.PopFirst(); key != "one" || value != 1 || !ok {
+		t.Error("PopFirst didn't pop first element")
+	}
+	if key, value, ok := sm.PopLast(); key != "three" || value != 3 || !ok {
+		t.Error("PopLast didn't pop last element")
+	}
+
+	sm.Delete("two")
+	if sm.Len() != 0 {
+		t.Error("Expecting empty map, length ", sm.Len())
+	}
+}
+
+func TestSyncGetOrSet(t *testing.T) {
+	sm := NewSync[string, int]()
+
+	if actual, loaded := sm.GetOrSet("key", 1); actual != 1 || loaded {
+		t.Error(fmt.Sprintf("Expecting 1, false -> Returned %v %v", actual, loaded))
+	}
+	if actual, loaded := sm.GetOrSet("key", 2); actual != 1 || !loaded {
+		t.Error(fmt.Sprintf("Expecting 1, true -> Returned %v %v", actual, loaded))
+	}
+}
+
+func TestSyncUpdate(t *testing.T) {
+	sm := NewSync[string, int]()
+	increment := func(value int, ok bool) (int, bool) {
+		return value + 1, true
+	}
+
+	sm.Update("a", increment)
+	sm.Update("b", increment)
+	if value, ok := sm.Update("a", increment); value != 2 || !ok {
+		t.Error(fmt.Sprintf("Expecting 2, true -> Returned %v %v", value, ok))
+	}
+	if key, _, _ := sm.GetFirst(); key != "a" {
+		t.Error("Update changed the key position")
+	}
+
+	// Delete from the update function
+	remove := func(value int, ok bool) (int, bool) {
+		return 0, false
+	}
+	if value, ok := sm.Update("a", remove); value != 0 || ok {
+		t.Error(fmt.Sprintf("Expecting 0, false -> Returned %v %v", value, ok))
+	}
+	if _, ok := sm.Get("a"); ok || sm.Len() != 1 {
+		t.Error("Update didn't delete the key")
+	}
+}
+
+func TestSyncSnapshotIter(t *testing.T) {
+	sm := NewSync[int, int]()
+	for k := 0; k < 5; k++ {
+		sm.Set(k, k)
+	}
+
+	// Writes after the iterator is created are not visible
+	iter := sm.Iter()
+	sm.Set(100, 100)
+	sm.Delete(0)
+
+	expected := 0
+	for key, _, ok := iter.Next(); ok; key, _, ok = iter.Next() {
+		if key != expected {
+			t.Error(fmt.Sprintf("Expecting key %v received %v", expected, key))
+		}
+		expected++
+	}
+	if expected != 5 {
+		t.Error("Snapshot iterator visited ", expected, " keys")
+	}
+
+	// Writing while ranging doesn't deadlock
+	for key := range sm.Backward() {
+		sm.Set(key+1000, key)
+	}
+	if sm.Len() != 10 {
+		t.Error("Expecting 10 keys, received ", sm.Len())
+	}
+
+	count := 0
+	sm.Range(func(key int, value int) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Error("Range didn't stop")
+	}
+}
+
+func TestSyncKeysValuesJSON(t *testing.T) {
+	sm := NewSync[string, int]()
+	sm.Set("b", 2)
+	sm.Set("a", 1)
+
+	// Writing while ranging doesn't deadlock or change the snapshot
+	var keys []string
+	for key := range sm.Keys() {
+		keys = append(keys, key)
+		sm.Set(key+key, 0)
+	}
+	var values []int
+	for value := range sm.Values() {
+		values = append(values, value)
+	}
+	if fmt.Sprint(keys) != "[b a]" || fmt.Sprint(values) != "[2 1 0 0]" {
+		t.Error("Invalid keys or values ", keys, values)
+	}
+
+	data, err := json.Marshal(sm)
+	if err != nil || string(data) != `{"b":2,"a":1,"bb":0,"aa":0}` {
+		t.Error("Invalid JSON encoding ", string(data), err)
+	}
+	decoded := NewSync[string, int]()
+	decoded.Set("z", 26)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != "OrderedMap[z:26 b:2 a:1 bb:0 aa:0]" {
+		t.Error("Invalid JSON decoding ", decoded)
+	}
+
+	var nilMap *SyncOrderedMap[string, int]
+	if data, _ := json.Marshal(nilMap); string(data) != "null" {
+		t.Error("Invalid nil map encoding ", string(data))
+	}
+}
+
+// Run with -race to detect unsynchronized access
+func TestSyncConcurrent(t *testing.T) {
+	sm := NewSync[int, int]()
+	counter := NewSync[string, int]()
+	workers, iterations := 8, 500
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				key := w*iterations + i
+				sm.Set(key, i)
+				sm.Get(key)
+				counter.Update("count", func(value int, ok bool) (int, bool) {
+					return value + 1, true
+				})
+				sm.GetOrSet(key%10, i)
+				if i%3 == 0 {
+					sm.MoveFirst(key)
+				}
+				if i%5 == 0 {
+					sm.PopLast()
+				}
+				if i%50 == 0 {
+					for range sm.All() {
+					}
+					sm.Range(func(key int, value int) bool { return true })
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if value, _ := counter.Get("count"); value != workers*iterations {
+		t.Error(fmt.Sprintf("Expecting counter %v received %v", workers*iterations, value))
+	}
+}
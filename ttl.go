@@ -0,0 +1,239 @@
+package orderedmap
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// TTLMap is an OrderedMap whose entries expire a fixed time after they are
+// set. Entries are kept in expiry order, so expired entries are always at the
+// front of the map and can be removed without scanning it. As every entry
+// gets the same time-to-live it is also insertion order, unless the clock
+// goes backwards.
+//
+// Expired entries are treated as missing, and removed by any operation on
+// the map, by Expire, or periodically by a janitor goroutine started with
+// StartJanitor. TTLMap is safe for concurrent use.
+type TTLMap[K comparable, V any] struct {
+	mu       sync.Mutex
+	om       *OrderedMap[K, ttlEntry[V]]
+	ttl      time.Duration
+	now      func() time.Time
+	onExpire func(key K, value V)
+}
+
+// A value and the moment it expires
+type ttlEntry[V any] struct {
+	value    V
+	deadline time.Time
+}
+
+// NewTTLMap creates an empty TTLMap whose entries expire ttl after being set
+func NewTTLMap[K comparable, V any](ttl time.Duration) *TTLMap[K, V] {
+	return &TTLMap[K, V]{
+		om:  New[K, ttlEntry[V]](),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// SetClock replaces the function used to read the current time, by default
+// time.Now. Moving the clock backwards doesn't restore expired entries.
+func (tm *TTLMap[K, V]) SetClock(now func() time.Time) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.now = now
+}
+
+// OnExpire sets a function called with every entry removed because it
+// expired. It is called without holding the map lock.
+func (tm *TTLMap[K, V]) OnExpire(fn func(key K, value V)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.onExpire = fn
+}
+
+// TTL returns the time-to-live of the map entries
+func (tm *TTLMap[K, V]) TTL() time.Duration {
+	return tm.ttl
+}
+
+// Len returns the number of entries that haven't expired
+func (tm *TTLMap[K, V]) Len() int {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	length := tm.om.Len()
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return length
+}
+
+// Set the key value with a new deadline. To keep the map in expiry order
+// an existing key is moved, as if it had been deleted and inserted again,
+// usually to the end unless the clock went backwards.
+func (tm *TTLMap[K, V]) Set(key K, value V) {
+	tm.mu.Lock()
+	now := tm.now()
+	expired := tm.expire(now)
+	tm.om.Delete(key)
+
+	// Insert after the entries expiring at the same moment or earlier
+	deadline := now.Add(tm.ttl)
+	mark := tm.om.root
+	for mark.Prev != tm.om.root && mark.Prev.Value.deadline.After(deadline) {
+		mark = mark.Prev
+	}
+	tm.om.insert(key, ttlEntry[V]{value: value, deadline: deadline}, mark)
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+}
+
+// Get the value of a key that hasn't expired
+func (tm *TTLMap[K, V]) Get(key K) (value V, ok bool) {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	entry, ok := tm.om.Get(key)
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return entry.value, ok
+}
+
+// Deadline returns the moment a key expires
+func (tm *TTLMap[K, V]) Deadline(key K) (deadline time.Time, ok bool) {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	entry, ok := tm.om.Get(key)
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return entry.deadline, ok
+}
+
+// GetFirst returns the key and value of the entry closest to expiring
+func (tm *TTLMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	key, entry, ok := tm.om.GetFirst()
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return key, entry.value, ok
+}
+
+// PopFirst removes and returns the entry closest to expiring
+func (tm *TTLMap[K, V]) PopFirst() (key K, value V, ok bool) {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	key, entry, ok := tm.om.PopFirst()
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return key, entry.value, ok
+}
+
+// Delete a key:value pair from the map.
+func (tm *TTLMap[K, V]) Delete(key K) {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	tm.om.Delete(key)
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+}
+
+// All returns an iterator over a snapshot of the entries that haven't
+// expired, in expiry order.
+func (tm *TTLMap[K, V]) All() iter.Seq2[K, V] {
+	tm.mu.Lock()
+	expired := tm.expire(tm.now())
+	snapshot := New[K, V]()
+	for key, entry := range tm.om.All() {
+		snapshot.Set(key, entry.value)
+	}
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	return snapshot.All()
+}
+
+// Expire removes the entries whose deadline is not after now, and returns
+// how many were removed.
+func (tm *TTLMap[K, V]) Expire(now time.Time) int {
+	tm.mu.Lock()
+	expired := tm.expire(now)
+	tm.mu.Unlock()
+
+	tm.notify(expired)
+	if expired == nil {
+		return 0
+	}
+	return expired.Len()
+}
+
+// StartJanitor starts a goroutine that removes expired entries every
+// interval, using the map clock. The returned function stops it.
+func (tm *TTLMap[K, V]) StartJanitor(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				tm.mu.Lock()
+				now := tm.now()
+				tm.mu.Unlock()
+				tm.Expire(now)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// Pop expired entries from the front of the map, must be called with the
+// lock held. Returns nil if no entry expired.
+func (tm *TTLMap[K, V]) expire(now time.Time) (expired *OrderedMap[K, V]) {
+	for {
+		key, entry, ok := tm.om.GetFirst()
+		if !ok || entry.deadline.After(now) {
+			break
+		}
+		tm.om.PopFirst()
+
+		if expired == nil {
+			expired = New[K, V]()
+		}
+		expired.Set(key, entry.value)
+	}
+	return expired
+}
+
+// Call the expiry callback, must be called without the lock held.
+func (tm *TTLMap[K, V]) notify(expired *OrderedMap[K, V]) {
+	if expired == nil {
+		return
+	}
+
+	tm.mu.Lock()
+	onExpire := tm.onExpire
+	tm.mu.Unlock()
+
+	if onExpire != nil {
+		for key, value := range expired.All() {
+			onExpire(key, value)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package orderedmap
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock controlled by the tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestTTLMap(ttl time.Duration) (*TTLMap[string, int], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	tm := NewTTLMap[string, int](ttl)
+	tm.SetClock(clock.Now)
+	return tm, clock
+}
+
+func TestTTLMap(t *testing.T) {
+	tm, clock := newTestTTLMap(time.Minute)
+
+	tm.Set("a", 1)
+	clock.Advance(30 * time.Second)
+	tm.Set("b", 2)
+
+	if value, ok := tm.Get("a"); value != 1 || !ok {
+		t.Error(fmt.Sprintf("Expecting 1, true -> Returned %v %v", value, ok))
+	}
+	if deadline, _ := tm.Deadline("b"); !deadline.Equal(clock.Now().Add(time.Minute)) {
+		t.Error("Invalid deadline ", deadline)
+	}
+
+	// a expires exactly at its deadline
+	clock.Advance(30 * time.Second)
+	if value, ok := tm.Get("a"); value != 0 || ok {
+		t.Error(fmt.Sprintf("Expecting 0, false -> Returned %v %v", value, ok))
+	}
+	if _, ok := tm.Get("b"); !ok || tm.Len() != 1 {
+		t.Error("Key expired before its deadline")
+	}
+
+	clock.Advance(time.Hour)
+	if tm.Len() != 0 {
+		t.Error("Expecting all keys expired, length ", tm.Len())
+	}
+}
+
+func TestTTLMapRefresh(t *testing.T) {
+	tm, clock := newTestTTLMap(time.Minute)
+
+	tm.Set("a", 1)
+	tm.Set("b", 2)
+	clock.Advance(45 * time.Second)
+
+	// Setting a key again refreshes its deadline and moves it to the end
+	tm.Set("a", 10)
+	if key, _, _ := tm.GetFirst(); key != "b" {
+		t.Error("Refreshed key not moved to the end")
+	}
+
+	clock.Advance(30 * time.Second)
+	if _, ok := tm.Get("b"); ok {
+		t.Error("Key b should have expired")
+	}
+	if value, ok := tm.Get("a"); value != 10 || !ok {
+		t.Error("Refreshed key expired")
+	}
+}
+
+func TestTTLMapClockBackwards(t *testing.T) {
+	tm, clock := newTestTTLMap(10 * time.Second)
+
+	tm.Set("a", 1)
+	clock.Advance(-50 * time.Second)
+	tm.Set("b", 2)
+	tm.Set("c", 3)
+	clock.Advance(20 * time.Second)
+
+	// b and c deadlines passed before a's
+	if value, ok := tm.Get("b"); value != 0 || ok {
+		t.Error(fmt.Sprintf("Expecting 0, false -> Returned %v %v", value, ok))
+	}
+	if key, _, _ := tm.GetFirst(); key != "a" || tm.Len() != 1 {
+		t.Error("Expecting only a left, first key ", key)
+	}
+}
+
+func TestTTLMapExpire(t *testing.T) {
+	tm, clock := newTestTTLMap(time.Minute)
+
+	var expired []string
+	tm.OnExpire(func(key string, value int) {
+		expired = append(expired, key)
+	})
+
+	for i, key := range []string{"a", "b", "c", "d"} {
+		tm.Set(key, i)
+		clock.Advance(10 * time.Second)
+	}
+	tm.Delete("b")
+
+	// Now is 40s, deadlines are a:60s b:70s c:80s d:90s
+	if n := tm.Expire(clock.Now().Add(45 * time.Second)); n != 2 {
+		t.Error("Expecting 2 expired entries, received ", n)
+	}
+	if fmt.Sprint(expired) != "[a c]" {
+		t.Error("Invalid expired keys ", expired)
+	}
+	if n := tm.Expire(clock.Now()); n != 0 {
+		t.Error("Expecting no expired entries, received ", n)
+	}
+
+	if key, value, ok := tm.PopFirst(); key != "d" || value != 3 || !ok {
+		t.Error("PopFirst didn't return the next entry to expire")
+	}
+
+	tm.Set("e", 5)
+	var keys []string
+	for key := range tm.All() {
+		keys = append(keys, key)
+	}
+	if fmt.Sprint(keys) != "[e]" {
+		t.Error("Invalid keys ", keys)
+	}
+}
+
+func TestTTLMapJanitor(t *testing.T) {
+	tm, clock := newTestTTLMap(time.Minute)
+
+	done := make(chan string, 1)
+	tm.OnExpire(func(key string, value int) {
+		done <- key
+	})
+	tm.Set("a", 1)
+
+	stop := tm.StartJanitor(time.Millisecond)
+	defer stop()
+
+	clock.Advance(time.Minute)
+	select {
+	case key := <-done:
+		if key != "a" {
+			t.Error("Janitor expired an invalid key ", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Janitor didn't expire the key")
+	}
+
+	stop()
+	stop() // Stopping twice is harmless
+}
@@ -0,0 +1,594 @@
+package orderedmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Files in a FileMap directory
+const (
+	fileMapSnapshot = "snapshot"
+	fileMapLog      = "log"
+)
+
+// File header: magic, format version, file kind and generation
+const (
+	fileMapMagic      = "OMAP"
+	fileMapVersion    = 2
+	fileMapHeaderSize = len(fileMapMagic) + 2 + 8
+)
+
+var fileMapCRC = crc32.MakeTable(crc32.Castagnoli)
+
+// Errors returned by FileMap
+var (
+	ErrFileMapClosed  = errors.New("orderedmap: FileMap is closed")
+	ErrCorruptFileMap = errors.New("orderedmap: corrupt FileMap file")
+)
+
+// FileMap is an OrderedMap whose contents survive restarts. Every Set, Delete
+// and Move is appended to a write-ahead log and synced to disk before it is
+// applied, and OpenFileMap rebuilds the map from the files in its directory.
+// Compact writes the map to a snapshot file and starts a new empty log.
+//
+// Log records are framed with their length and a CRC-32C checksum. A record
+// torn by a crash while it was being written can only be the last one, it is
+// detected and discarded. Any other damage is reported as corruption.
+//
+// Keys and values are encoded with encoding/gob, so interface keys and values
+// must hold types registered with gob.Register. Key types that wouldn't be
+// equal after a restart, such as pointers or structs with unexported fields,
+// are rejected by OpenFileMap.
+//
+// FileMap is safe for concurrent use.
+type FileMap[K comparable, V any] struct {
+	mu         sync.Mutex
+	dir        string
+	om         *OrderedMap[K, V]
+	log        *os.File
+	logSize    int64
+	logRecords int
+	generation uint64
+}
+
+// A log or snapshot record
+type fileMapRecord[K comparable, V any] struct {
+	Op    string
+	Key   K
+	Value V
+	Last  bool
+}
+
+// Record operations
+const (
+	fileMapSet    = "set"
+	fileMapDelete = "delete"
+	fileMapMove   = "move"
+)
+
+// OpenFileMap opens the FileMap stored in dir, creating the directory if it
+// doesn't exist. The map is rebuilt from the snapshot and the log records
+// written after it, a torn record at the end of the log is truncated. Files
+// that can't be read safely, such as a log of an unknown format version, are
+// left untouched and ErrCorruptFileMap is returned.
+func OpenFileMap[K comparable, V any](dir string) (*FileMap[K, V], error) {
+	if err := checkFileMapKey(reflect.TypeFor[K]()); err != nil {
+		return nil, fmt.Errorf("orderedmap: unsupported FileMap key type: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	fm := &FileMap[K, V]{dir: dir, om: New[K, V]()}
+
+	if err := fm.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := fm.openLog(); err != nil {
+		return nil, err
+	}
+	return fm, nil
+}
+
+// Load the snapshot file if there is one
+func (fm *FileMap[K, V]) loadSnapshot() error {
+	file, err := os.Open(filepath.Join(fm.dir, fileMapSnapshot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Snapshots are renamed into place once complete, so unlike the log any
+	// damage is an error.
+	reader := bufio.NewReader(file)
+	if fm.generation, err = readFileMapHeader(reader, 'S'); err != nil {
+		return fmt.Errorf("%w: %v: %v", ErrCorruptFileMap, fileMapSnapshot, err)
+	}
+	offset := int64(fileMapHeaderSize)
+	for {
+		payload, err := readFileMapFrame(reader, info.Size()-offset)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v: %v", ErrCorruptFileMap, fileMapSnapshot, err)
+		}
+		if err := fm.replay(payload); err != nil {
+			return err
+		}
+		offset += int64(8 + len(payload))
+	}
+}
+
+// Open the log and replay its records, or start a new one if it doesn't
+// exist, its header is torn or it belongs to an older generation.
+func (fm *FileMap[K, V]) openLog() error {
+	path := filepath.Join(fm.dir, fileMapLog)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	generation, err := readFileMapHeader(reader, 'L')
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF || (err == nil && generation < fm.generation):
+		// New log, torn header, or left by a crash after a snapshot
+		// replaced it, when its records are already in the snapshot.
+		file.Close()
+		return fm.resetLog()
+	case err != nil:
+		file.Close()
+		return fmt.Errorf("%w: %v: %v", ErrCorruptFileMap, fileMapLog, err)
+	case generation > fm.generation:
+		// The records apply to a snapshot that is missing
+		file.Close()
+		return fmt.Errorf("%w: %v: generation %d newer than snapshot %d",
+			ErrCorruptFileMap, fileMapLog, generation, fm.generation)
+	}
+
+	// Replay up to the end, or to a record torn by a crash
+	offset := int64(fileMapHeaderSize)
+	for {
+		payload, err := readFileMapFrame(reader, info.Size()-offset)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("%w: %v: offset %d: %v", ErrCorruptFileMap, fileMapLog, offset, err)
+		}
+		if err := fm.replay(payload); err != nil {
+			file.Close()
+			return err
+		}
+		offset += int64(8 + len(payload))
+		fm.logRecords++
+	}
+
+	if offset < info.Size() {
+		if err := file.Truncate(offset); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	fm.log, fm.logSize = file, offset
+	return nil
+}
+
+// Apply a record to the map
+func (fm *FileMap[K, V]) replay(payload []byte) error {
+	var record fileMapRecord[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&record); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptFileMap, err)
+	}
+
+	switch record.Op {
+	case fileMapSet:
+		fm.om.Set(record.Key, record.Value)
+	case fileMapDelete:
+		fm.om.Delete(record.Key)
+	case fileMapMove:
+		fm.om.Move(record.Key, record.Last)
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrCorruptFileMap, record.Op)
+	}
+	return nil
+}
+
+// Len returns the number of elements in the Map
+func (fm *FileMap[K, V]) Len() int {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.om.Len()
+}
+
+// Get the value of an existing key
+func (fm *FileMap[K, V]) Get(key K) (value V, ok bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.om.Get(key)
+}
+
+// GetFirst returns the key and value for the first element
+func (fm *FileMap[K, V]) GetFirst() (key K, value V, ok bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.om.GetFirst()
+}
+
+// GetLast returns the key and value for the last element
+func (fm *FileMap[K, V]) GetLast() (key K, value V, ok bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.om.GetLast()
+}
+
+// All returns an iterator over a snapshot of the map key:value pairs
+func (fm *FileMap[K, V]) All() iter.Seq2[K, V] {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.om.Clone().All()
+}
+
+// Set the key value, see OrderedMap.Set. The map is unchanged if the change
+// can't be written to the log, and if the partly written record can't be
+// removed either the map is closed.
+func (fm *FileMap[K, V]) Set(key K, value V) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if err := fm.append(fileMapRecord[K, V]{Op: fileMapSet, Key: key, Value: value}); err != nil {
+		return err
+	}
+	fm.om.Set(key, value)
+	return nil
+}
+
+// Delete a key:value pair from the map.
+func (fm *FileMap[K, V]) Delete(key K) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if _, ok := fm.om.table[key]; !ok {
+		return nil
+	}
+	if err := fm.append(fileMapRecord[K, V]{Op: fileMapDelete, Key: key}); err != nil {
+		return err
+	}
+	fm.om.Delete(key)
+	return nil
+}
+
+// Move an existing key to either end of the map
+func (fm *FileMap[K, V]) Move(key K, last bool) (ok bool, err error) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if _, ok := fm.om.table[key]; !ok {
+		return false, nil
+	}
+	if err := fm.append(fileMapRecord[K, V]{Op: fileMapMove, Key: key, Last: last}); err != nil {
+		return false, err
+	}
+	return fm.om.Move(key, last), nil
+}
+
+// MoveLast is a shortcut to Move a key to the end of the map
+func (fm *FileMap[K, V]) MoveLast(key K) (ok bool, err error) {
+	return fm.Move(key, true)
+}
+
+// MoveFirst is a shortcut to Move a key to the beginning of the map
+func (fm *FileMap[K, V]) MoveFirst(key K) (ok bool, err error) {
+	return fm.Move(key, false)
+}
+
+// Append a record to the log and sync it, must be called with the lock held
+func (fm *FileMap[K, V]) append(record fileMapRecord[K, V]) error {
+	if fm.log == nil {
+		return ErrFileMapClosed
+	}
+	frame, err := encodeFileMapFrame(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = fm.log.Write(frame)
+	if err == nil {
+		err = fm.log.Sync()
+	}
+	if err != nil {
+		// Remove any part of the record that was written, so later records
+		// aren't appended after it. If that fails the log is in an unknown
+		// state and the map is closed.
+		rollbackErr := fm.log.Truncate(fm.logSize)
+		if rollbackErr == nil {
+			_, rollbackErr = fm.log.Seek(fm.logSize, io.SeekStart)
+		}
+		if rollbackErr != nil {
+			fm.log.Close()
+			fm.log = nil
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	fm.logSize += int64(len(frame))
+	fm.logRecords++
+	return nil
+}
+
+// Compact writes the map contents to a new snapshot and empties the log. A
+// crash at any point leaves either the old or the new snapshot in place. If
+// the snapshot was written but the new log can't be created the map is
+// closed, as further changes couldn't be recovered.
+func (fm *FileMap[K, V]) Compact() error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if fm.log == nil {
+		return ErrFileMapClosed
+	}
+
+	generation := fm.generation + 1
+	err := writeFileMapFile(fm.dir, fileMapSnapshot, 'S', generation, func(w io.Writer) error {
+		for key, value := range fm.om.All() {
+			frame, err := encodeFileMapFrame(fileMapRecord[K, V]{Op: fileMapSet, Key: key, Value: value})
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(frame); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The snapshot is in place, the old log is ignored from now on because of
+	// its generation.
+	fm.generation = generation
+	fm.log.Close()
+	fm.log = nil
+	return fm.resetLog()
+}
+
+// StartCompactor starts a goroutine that compacts the map every interval if
+// the log has any record. Errors are passed to onError, which can be nil.
+// The returned function stops it.
+func (fm *FileMap[K, V]) StartCompactor(interval time.Duration, onError func(err error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				fm.mu.Lock()
+				pending := fm.logRecords > 0 && fm.log != nil
+				fm.mu.Unlock()
+
+				if pending {
+					if err := fm.Compact(); err != nil && onError != nil {
+						onError(err)
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// Close the log file, the map can't be used afterwards
+func (fm *FileMap[K, V]) Close() error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if fm.log == nil {
+		return ErrFileMapClosed
+	}
+	err := fm.log.Close()
+	fm.log = nil
+	return err
+}
+
+// Replace the log with an empty one for the current generation
+func (fm *FileMap[K, V]) resetLog() error {
+	err := writeFileMapFile(fm.dir, fileMapLog, 'L', fm.generation, func(w io.Writer) error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filepath.Join(fm.dir, fileMapLog), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return err
+	}
+	fm.log, fm.logSize, fm.logRecords = file, int64(fileMapHeaderSize), 0
+	return nil
+}
+
+// Atomically replace a file: write it to a temporary file, sync it and rename
+// it into place.
+func writeFileMapFile(dir string, name string, kind byte, generation uint64, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(dir, name+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails once renamed
+
+	writer := bufio.NewWriter(tmp)
+	writer.Write(fileMapHeader(kind, generation))
+	err = write(writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, name))
+	}
+	if err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// Sync a directory so a rename in it is durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func fileMapHeader(kind byte, generation uint64) []byte {
+	header := make([]byte, 0, fileMapHeaderSize)
+	header = append(header, fileMapMagic...)
+	header = append(header, fileMapVersion, kind)
+	return binary.LittleEndian.AppendUint64(header, generation)
+}
+
+// Read and check a file header, returns its generation
+func readFileMapHeader(r io.Reader, kind byte) (generation uint64, err error) {
+	header := make([]byte, fileMapHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(header[:len(fileMapMagic)], []byte(fileMapMagic)) || header[len(fileMapMagic)+1] != kind {
+		return 0, errors.New("invalid header")
+	}
+	if version := header[len(fileMapMagic)]; version != fileMapVersion {
+		return 0, fmt.Errorf("unsupported version %d", version)
+	}
+	return binary.LittleEndian.Uint64(header[len(fileMapMagic)+2:]), nil
+}
+
+// Frame a record: payload length, payload CRC-32C and gob payload
+func encodeFileMapFrame[K comparable, V any](record fileMapRecord[K, V]) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(record); err != nil {
+		return nil, fmt.Errorf("orderedmap: %w", err)
+	}
+	payload := buffer.Bytes()
+
+	frame := make([]byte, 8, 8+len(payload))
+	binary.LittleEndian.PutUint32(frame, uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[4:], crc32.Checksum(payload, fileMapCRC))
+	return append(frame, payload...), nil
+}
+
+var (
+	gobEncoderType    = reflect.TypeFor[gob.GobEncoder]()
+	binaryMarshalType = reflect.TypeFor[encoding.BinaryMarshaler]()
+)
+
+// Check that keys of a type are equal to themselves after being encoded and
+// decoded. Types with their own encoding are trusted.
+func checkFileMapKey(t reflect.Type) error {
+	for _, marshaler := range []reflect.Type{gobEncoderType, binaryMarshalType} {
+		if t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler) {
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Errorf("%v is compared by identity", t)
+	case reflect.Array:
+		return checkFileMapKey(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				return fmt.Errorf("%v has unexported field %v", t, field.Name)
+			}
+			if err := checkFileMapKey(field.Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Read a frame payload, remaining is the size of the file after the current
+// position. Returns io.EOF at the end of the file, io.ErrUnexpectedEOF if the
+// frame is cut by the end of the file, and another error if its checksum
+// doesn't match.
+func readFileMapFrame(r io.Reader, remaining int64) ([]byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// The length is checked before allocating, so a damaged header can't
+	// claim more than the file holds.
+	length := binary.LittleEndian.Uint32(header[:])
+	if int64(length) > remaining-8 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(payload, fileMapCRC) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	return payload, nil
+}
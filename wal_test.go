@@ -0,0 +1,330 @@
+package orderedmap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestFileMap(t *testing.T, dir string) *FileMap[string, int] {
+	t.Helper()
+	fm, err := OpenFileMap[string, int](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fm
+}
+
+func fileMapString[K comparable, V any](fm *FileMap[K, V]) string {
+	om := New[K, V]()
+	om.SetSeq(fm.All())
+	return om.String()
+}
+
+func TestFileMapReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	fm := openTestFileMap(t, dir)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		if err := fm.Set(key, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fm.Set("a", 10)
+	fm.Delete("b")
+	fm.Delete("z")
+	if ok, err := fm.MoveFirst("d"); !ok || err != nil {
+		t.Error("MoveFirst failed ", ok, err)
+	}
+	if ok, _ := fm.MoveLast("z"); ok {
+		t.Error("Moved a missing key")
+	}
+	if err := fm.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Set("e", 5); !errors.Is(err, ErrFileMapClosed) {
+		t.Error("Expecting closed error, received ", err)
+	}
+
+	fm = openTestFileMap(t, dir)
+	defer fm.Close()
+	if result := fileMapString(fm); result != "OrderedMap[d:3 a:10 c:2]" {
+		t.Error("Invalid map after reopening ", result)
+	}
+	if value, ok := fm.Get("a"); value != 10 || !ok || fm.Len() != 3 {
+		t.Error("Invalid Get after reopening ", value, ok)
+	}
+}
+
+func TestFileMapTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, fileMapLog)
+
+	fm := openTestFileMap(t, dir)
+	fm.Set("a", 1)
+	fm.Set("b", 2)
+	fm.Close()
+	info, _ := os.Stat(logPath)
+	size := info.Size()
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+	}{
+		{"partial frame", func(data []byte) []byte {
+			return data[:len(data)-3]
+		}},
+		{"partial header", func(data []byte) []byte {
+			frame, _ := encodeFileMapFrame(fileMapRecord[string, int]{Op: fileMapSet, Key: "c", Value: 3})
+			return append(data, frame[:5]...)
+		}},
+	}
+	for _, test := range tests {
+		data, _ := os.ReadFile(logPath)
+		os.WriteFile(logPath, test.corrupt(data[:size]), 0o644)
+
+		fm = openTestFileMap(t, dir)
+		expected := "OrderedMap[a:1]"
+		if test.name == "partial header" {
+			expected = "OrderedMap[a:1 b:2]"
+		}
+		if result := fileMapString(fm); result != expected {
+			t.Error(fmt.Sprintf("%v: Expecting %v received %v", test.name, expected, result))
+		}
+
+		// The damaged record was truncated, so new records are kept
+		fm.Set("b", 2)
+		fm.Close()
+		fm = openTestFileMap(t, dir)
+		if result := fileMapString(fm); result != "OrderedMap[a:1 b:2]" {
+			t.Error(fmt.Sprintf("%v: Record after truncation lost %v", test.name, result))
+		}
+		fm.Close()
+	}
+}
+
+func TestFileMapCorruptLog(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, fileMapLog)
+
+	fm := openTestFileMap(t, dir)
+	fm.Set("a", 1)
+	fm.Set("b", 2)
+	fm.Set("c", 3)
+	fm.Close()
+	original, _ := os.ReadFile(logPath)
+	frame, _ := encodeFileMapFrame(fileMapRecord[string, int]{Op: fileMapSet, Key: "a", Value: 1})
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte)
+	}{
+		{"middle frame", func(data []byte) {
+			data[fileMapHeaderSize+len(frame)-2] ^= 0xff
+		}},
+		{"last frame checksum", func(data []byte) {
+			data[len(data)-2] ^= 0xff
+		}},
+		{"length", func(data []byte) {
+			// Within the file, larger frames are handled as torn
+			data[fileMapHeaderSize]++
+		}},
+	}
+	for _, test := range tests {
+		data := append([]byte(nil), original...)
+		test.corrupt(data)
+		os.WriteFile(logPath, data, 0o644)
+
+		if _, err := OpenFileMap[string, int](dir); !errors.Is(err, ErrCorruptFileMap) {
+			t.Error(test.name, ": expecting corrupt log error, received ", err)
+		}
+		if after, _ := os.ReadFile(logPath); len(after) != len(data) {
+			t.Error(test.name, ": log truncated from ", len(data), " to ", len(after))
+		}
+	}
+
+	// A length larger than the file isn't allocated
+	data := append([]byte(nil), original[:fileMapHeaderSize]...)
+	data = append(data, 0xff, 0xff, 0xff, 0x3f, 0, 0, 0, 0)
+	os.WriteFile(logPath, data, 0o644)
+	fm = openTestFileMap(t, dir)
+	if fm.Len() != 0 {
+		t.Error("Torn record replayed")
+	}
+	fm.Close()
+}
+
+func TestFileMapCompact(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, fileMapLog)
+
+	fm := openTestFileMap(t, dir)
+	for i := 0; i < 100; i++ {
+		fm.Set(fmt.Sprint(i%10), i)
+	}
+	fm.MoveFirst("9")
+	staleLog, _ := os.ReadFile(logPath)
+
+	if err := fm.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(logPath); info.Size() != int64(fileMapHeaderSize) {
+		t.Error("Log not emptied by Compact ", info.Size())
+	}
+	fm.Set("x", 1)
+	fm.Close()
+
+	fm = openTestFileMap(t, dir)
+	expected := "OrderedMap[9:99 0:90 1:91 2:92 3:93 4:94 5:95 6:96 7:97 8:98 x:1]"
+	if result := fileMapString(fm); result != expected {
+		t.Error(fmt.Sprintf("Expecting %v received %v", expected, result))
+	}
+	fm.Close()
+
+	// A crash after the snapshot was replaced but before the log was leaves
+	// the old log, which is ignored.
+	os.WriteFile(logPath, staleLog, 0o644)
+	fm = openTestFileMap(t, dir)
+	defer fm.Close()
+	expected = "OrderedMap[9:99 0:90 1:91 2:92 3:93 4:94 5:95 6:96 7:97 8:98]"
+	if result := fileMapString(fm); result != expected {
+		t.Error(fmt.Sprintf("Stale log: Expecting %v received %v", expected, result))
+	}
+}
+
+func TestFileMapCompactor(t *testing.T) {
+	dir := t.TempDir()
+	fm := openTestFileMap(t, dir)
+	defer fm.Close()
+
+	stop := fm.StartCompactor(time.Millisecond, func(err error) {
+		t.Error(err)
+	})
+	fm.Set("a", 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, fileMapSnapshot)); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Compactor didn't write a snapshot")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	stop()
+}
+
+func TestFileMapCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	fm := openTestFileMap(t, dir)
+	fm.Set("a", 1)
+	fm.Compact()
+	fm.Close()
+
+	path := filepath.Join(dir, fileMapSnapshot)
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	if _, err := OpenFileMap[string, int](dir); !errors.Is(err, ErrCorruptFileMap) {
+		t.Error("Expecting corrupt snapshot error, received ", err)
+	}
+}
+
+func TestFileMapUnreadableLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		header func(header []byte)
+	}{
+		{"future version", func(header []byte) { header[len(fileMapMagic)] = fileMapVersion + 1 }},
+		{"invalid kind", func(header []byte) { header[len(fileMapMagic)+1] = 'X' }},
+		{"newer generation", func(header []byte) { header[len(fileMapMagic)+2]++ }},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		fm := openTestFileMap(t, dir)
+		fm.Set("a", 1)
+		fm.Set("b", 2)
+		fm.Close()
+
+		path := filepath.Join(dir, fileMapLog)
+		data, _ := os.ReadFile(path)
+		test.header(data)
+		os.WriteFile(path, data, 0o644)
+
+		if _, err := OpenFileMap[string, int](dir); !errors.Is(err, ErrCorruptFileMap) {
+			t.Error(test.name, ": expecting corrupt log error, received ", err)
+		}
+
+		// The log must be left untouched
+		if after, _ := os.ReadFile(path); len(after) != len(data) {
+			t.Error(test.name, ": log truncated from ", len(data), " to ", len(after))
+		}
+	}
+}
+
+func TestFileMapFailedWrite(t *testing.T) {
+	dir := t.TempDir()
+	fm := openTestFileMap(t, dir)
+	fm.Set("a", 1)
+
+	// The record can't be written or removed, so the map is closed
+	fm.log.Close()
+	if err := fm.Set("b", 2); err == nil || errors.Is(err, ErrFileMapClosed) {
+		t.Error("Expecting a write error, received ", err)
+	}
+	if err := fm.Set("c", 3); !errors.Is(err, ErrFileMapClosed) {
+		t.Error("Expecting closed error, received ", err)
+	}
+	if _, ok := fm.Get("b"); ok {
+		t.Error("Failed change applied")
+	}
+
+	fm = openTestFileMap(t, dir)
+	defer fm.Close()
+	if result := fileMapString(fm); result != "OrderedMap[a:1]" {
+		t.Error("Invalid map after a failed write ", result)
+	}
+}
+
+func TestFileMapKeyTypes(t *testing.T) {
+	dir := t.TempDir()
+	fm, err := OpenFileMap[any, int](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm.Set(1, 1)
+	fm.Set("1", 2)
+	fm.Set(1.5, 3)
+	fm.Close()
+
+	fm, _ = OpenFileMap[any, int](dir)
+	defer fm.Close()
+	for key, expected := range map[any]int{1: 1, "1": 2, 1.5: 3} {
+		if value, ok := fm.Get(key); value != expected || !ok {
+			t.Error(fmt.Sprintf("%#v: expecting %v received %v %v", key, expected, value, ok))
+		}
+	}
+
+	type point struct{ X, Y int }
+	points, err := OpenFileMap[point, string](t.TempDir())
+	if err != nil {
+		t.Error("Exported struct key rejected ", err)
+	} else {
+		points.Close()
+	}
+
+	type private struct{ x int }
+	if _, err := OpenFileMap[private, int](t.TempDir()); err == nil {
+		t.Error("Struct key with unexported fields accepted")
+	}
+	if _, err := OpenFileMap[*int, int](t.TempDir()); err == nil {
+		t.Error("Pointer key accepted")
+	}
+}
@@ -0,0 +1,807 @@
+package orderedmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The YAML support is a small built-in codec covering the block style used by
+// configuration files: mappings, sequences, plain and quoted scalars, flow
+// collections, literal and folded block scalars and comments. Anchors,
+// aliases, tags and multiple documents are not supported.
+//
+// Values go through their JSON representation on the way in and out, so any
+// value that can be encoded with encoding/json can be encoded as YAML, and
+// mappings nested inside interface{} values are decoded as
+// *OrderedMap[string, interface{}] keeping document order.
+
+// EncodeYAML writes the map as a YAML block mapping, with its keys in
+// insertion order.
+func (om *OrderedMap[K, V]) EncodeYAML(w io.Writer) error {
+	data, err := json.Marshal(om)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	tree, err := decodeNestedJSON(decoder)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if mapping, ok := tree.(*OrderedMap[string, interface{}]); ok && mapping.Len() > 0 {
+		writeYAMLMapping(&buffer, mapping, 0)
+	} else {
+		buffer.WriteString(yamlScalar(tree) + "\n")
+	}
+
+	_, err = w.Write(buffer.Bytes())
+	return err
+}
+
+// DecodeYAML reads a YAML mapping and inserts its keys in document order.
+// As with UnmarshalJSON existing keys are kept, and an empty or null
+// document leaves the map unchanged.
+func (om *OrderedMap[K, V]) DecodeYAML(r io.Reader) error {
+	parser, err := newYAMLParser(r)
+	if err != nil {
+		return err
+	}
+
+	if !parser.skipBlank() {
+		return nil
+	}
+	tree, err := parser.parseNode(0)
+	if err != nil {
+		return err
+	}
+	if parser.skipBlank() {
+		return parser.errorf("unexpected content")
+	}
+
+	if tree == nil {
+		return nil
+	}
+	mapping, ok := tree.(*OrderedMap[string, interface{}])
+	if !ok {
+		return fmt.Errorf("orderedmap: cannot decode YAML %T into OrderedMap", tree)
+	}
+
+	om.init()
+	for name, decoded := range mapping.All() {
+		key, err := decodeJSONKey[K](name)
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if ptr, ok := any(&value).(*interface{}); ok {
+			*ptr = decoded
+		} else if err := setYAMLValue(reflect.ValueOf(&value).Elem(), decoded); err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+	return nil
+}
+
+// Store a decoded YAML value in a typed value through its JSON encoding.
+// Infinities and NaN have no JSON encoding, so they are set directly, also as
+// sequence items.
+func setYAMLValue(target reflect.Value, decoded interface{}) error {
+	if f, ok := decoded.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+		switch target.Kind() {
+		case reflect.Float32, reflect.Float64:
+			target.SetFloat(f)
+			return nil
+		case reflect.Interface:
+			target.Set(reflect.ValueOf(f))
+			return nil
+		}
+		return fmt.Errorf("orderedmap: cannot decode YAML %v into %v", f, target.Type())
+	}
+	if items, ok := decoded.([]interface{}); ok && target.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setYAMLValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	}
+
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target.Addr().Interface())
+}
+
+//
+// Encoder
+//
+
+func writeYAMLMapping(buffer *bytes.Buffer, mapping *OrderedMap[string, interface{}], indent int) {
+	for key, value := range mapping.All() {
+		buffer.WriteString(strings.Repeat(" ", indent))
+		buffer.WriteString(yamlScalar(key))
+		buffer.WriteByte(':')
+
+		// Nested collections start on the next line
+		switch v := value.(type) {
+		case *OrderedMap[string, interface{}]:
+			if v.Len() > 0 {
+				buffer.WriteByte('\n')
+				writeYAMLMapping(buffer, v, indent+2)
+				continue
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				buffer.WriteByte('\n')
+				writeYAMLSequence(buffer, v, indent+2)
+				continue
+			}
+		}
+		buffer.WriteString(" " + yamlScalar(value) + "\n")
+	}
+}
+
+func writeYAMLSequence(buffer *bytes.Buffer, sequence []interface{}, indent int) {
+	for _, value := range sequence {
+		buffer.WriteString(strings.Repeat(" ", indent))
+		buffer.WriteString("- ")
+
+		// Nested collections start on the dash line, so the indentation
+		// of their first line is replaced by the dash.
+		var nested bytes.Buffer
+		switch v := value.(type) {
+		case *OrderedMap[string, interface{}]:
+			if v.Len() > 0 {
+				writeYAMLMapping(&nested, v, indent+2)
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				writeYAMLSequence(&nested, v, indent+2)
+			}
+		}
+
+		if nested.Len() > 0 {
+			buffer.Write(nested.Bytes()[indent+2:])
+		} else {
+			buffer.WriteString(yamlScalar(value) + "\n")
+		}
+	}
+}
+
+// Text for a scalar or empty collection value
+func yamlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		if yamlNeedsQuotes(v) {
+			return strconv.Quote(v)
+		}
+		return v
+	case *OrderedMap[string, interface{}]:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return fmt.Sprint(value)
+}
+
+// Check if a string would be read back as something else if left unquoted
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	if _, isString := resolveYAMLPlain(s).(string); !isString {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Decoder
+//
+
+type yamlLine struct {
+	number int    // Line number starting from 1
+	raw    string // Line without line break
+	indent int    // Leading spaces
+	text   string // Line without indentation and comments
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+var (
+	yamlIntRegexp   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatRegexp = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+func newYAMLParser(r io.Reader) (*yamlParser, error) {
+	parser := &yamlParser{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt32)
+	for number := 1; scanner.Scan(); number++ {
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		text := strings.TrimLeft(raw, " ")
+		line := yamlLine{
+			number: number,
+			raw:    raw,
+			indent: len(raw) - len(text),
+			text:   stripYAMLComment(text),
+		}
+		// Document markers, only a single document is supported
+		if line.indent == 0 && (line.text == "---" || line.text == "...") {
+			if line.text == "..." || parser.hasContent() {
+				break
+			}
+			continue
+		}
+		parser.lines = append(parser.lines, line)
+	}
+
+	return parser, scanner.Err()
+}
+
+func (p *yamlParser) hasContent() bool {
+	for _, line := range p.lines {
+		if line.text != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	number := 0
+	if p.pos < len(p.lines) {
+		number = p.lines[p.pos].number
+	} else if len(p.lines) > 0 {
+		number = p.lines[len(p.lines)-1].number
+	}
+	return fmt.Errorf("orderedmap: YAML line %d: %s", number, fmt.Sprintf(format, args...))
+}
+
+// Tabs can't be used for indentation, except inside block scalars where they
+// are content, so the lines are checked as they are parsed.
+func (p *yamlParser) checkTabs() error {
+	if strings.HasPrefix(p.lines[p.pos].text, "\t") {
+		return p.errorf("tabs are not allowed in indentation")
+	}
+	return nil
+}
+
+// Advance to the next line with content, returns false at the end of input
+func (p *yamlParser) skipBlank() bool {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+	return p.pos < len(p.lines)
+}
+
+// Parse the node starting on the current line
+func (p *yamlParser) parseNode(minIndent int) (interface{}, error) {
+	if err := p.checkTabs(); err != nil {
+		return nil, err
+	}
+	line := p.lines[p.pos]
+	if line.indent < minIndent {
+		return nil, nil
+	}
+
+	if isYAMLSequenceItem(line.text) {
+		return p.parseSequence(line.indent)
+	}
+	if _, _, ok := splitYAMLKey(line.text); ok {
+		return p.parseMapping(line.indent)
+	}
+
+	p.pos++
+	value, err := parseYAMLScalar(line.text)
+	if err != nil {
+		p.pos--
+		return nil, p.errorf("%v", err)
+	}
+	if p.skipBlank() && p.lines[p.pos].indent > line.indent {
+		return nil, p.errorf("multi-line plain scalars are not supported")
+	}
+	return value, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	mapping := New[string, interface{}]()
+
+	for p.skipBlank() {
+		if err := p.checkTabs(); err != nil {
+			return nil, err
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+		if isYAMLSequenceItem(line.text) {
+			break
+		}
+
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, p.errorf("expecting a mapping key")
+		}
+		if _, ok := mapping.Get(key); ok {
+			return nil, p.errorf("duplicate mapping key %q", key)
+		}
+
+		value, err := p.parseEntry(rest, indent, true)
+		if err != nil {
+			return nil, err
+		}
+		mapping.Set(key, value)
+	}
+
+	return mapping, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	sequence := []interface{}{}
+
+	for p.skipBlank() {
+		if err := p.checkTabs(); err != nil {
+			return nil, err
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent || !isYAMLSequenceItem(line.text) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("bad indentation")
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" || isYAMLBlockScalar(rest) {
+			value, err := p.parseEntry(rest, indent, false)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			continue
+		}
+
+		// Replace the dash by indentation and parse the rest of the line as
+		// a node, so mappings and sequences can start on the dash line.
+		offset := len(line.text) - len(rest)
+		p.lines[p.pos].indent += offset
+		p.lines[p.pos].text = rest
+		value, err := p.parseNode(indent + offset)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+	}
+
+	return sequence, nil
+}
+
+// Parse the value after a mapping key or sequence dash on the current line
+func (p *yamlParser) parseEntry(rest string, indent int, inMapping bool) (interface{}, error) {
+	p.pos++
+
+	if isYAMLBlockScalar(rest) {
+		return p.parseBlockScalar(rest, indent)
+	}
+
+	if rest != "" {
+		if _, _, ok := splitYAMLKey(rest); ok {
+			// Nested block mappings must start on their own line
+			p.pos--
+			return nil, p.errorf("mapping values are not allowed here")
+		}
+		value, err := parseYAMLScalar(rest)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%v", err)
+		}
+		if p.skipBlank() && p.lines[p.pos].indent > indent {
+			return nil, p.errorf("multi-line plain scalars are not supported")
+		}
+		return value, nil
+	}
+
+	// Nested block, sequences under a mapping key can use the key indentation
+	if !p.skipBlank() {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	if next.indent > indent || (inMapping && next.indent == indent && isYAMLSequenceItem(next.text)) {
+		return p.parseNode(indent)
+	}
+	return nil, nil
+}
+
+// Parse the content of a literal (|) or folded (>) block scalar
+func (p *yamlParser) parseBlockScalar(header string, indent int) (interface{}, error) {
+	chomping := ""
+	if len(header) > 1 {
+		chomping = header[1:]
+	}
+	if chomping != "" && chomping != "-" && chomping != "+" {
+		p.pos--
+		return nil, p.errorf("unsupported block scalar header %q", header)
+	}
+
+	var lines []string
+	contentIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line.raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if line.indent <= indent || (contentIndent >= 0 && line.indent < contentIndent) {
+			break
+		}
+		if contentIndent < 0 {
+			contentIndent = line.indent
+		}
+		lines = append(lines, line.raw[contentIndent:])
+	}
+
+	// Separate trailing blank lines for chomping
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	trailing := len(lines) - end
+	lines = lines[:end]
+
+	var text string
+	if header[0] == '|' {
+		text = strings.Join(lines, "\n")
+	} else {
+		text = foldYAMLLines(lines)
+	}
+
+	switch {
+	case len(lines) == 0:
+	case chomping == "-":
+	case chomping == "+":
+		text += strings.Repeat("\n", trailing+1)
+	default:
+		text += "\n"
+	}
+	return text, nil
+}
+
+// Join folded block scalar lines, line breaks are replaced by spaces except
+// around empty or more indented lines.
+func foldYAMLLines(lines []string) string {
+	var builder strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case line == "" || prev == "":
+				if prev != "" {
+					// The break before the first empty line is dropped
+					break
+				}
+				builder.WriteByte('\n')
+			case strings.HasPrefix(line, " ") || strings.HasPrefix(prev, " "):
+				builder.WriteByte('\n')
+			default:
+				builder.WriteByte(' ')
+			}
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLBlockScalar(text string) bool {
+	return strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">")
+}
+
+// Split a "key: value" line, the key can be quoted
+func splitYAMLKey(text string) (key string, rest string, ok bool) {
+	if text == "" || strings.ContainsAny(text[:1], "[{") {
+		return "", "", false
+	}
+
+	end := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end = yamlQuoteEnd(text)
+		if end < 0 {
+			return "", "", false
+		}
+	}
+
+	for i := end; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			keyText := strings.TrimSpace(text[:i])
+			if text[0] == '"' || text[0] == '\'' {
+				if i != end {
+					return "", "", false
+				}
+				value, err := parseYAMLScalar(keyText)
+				if err != nil {
+					return "", "", false
+				}
+				key = value.(string)
+			} else {
+				key = keyText
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// Index just past the closing quote of a quoted string at the start of text
+func yamlQuoteEnd(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// Remove a trailing comment, a # starts a comment at the start of the line
+// or after a space, but not inside quotes.
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" [{,:", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return strings.TrimRight(text[:i], " \t")
+		}
+	}
+	return strings.TrimRight(text, " \t")
+}
+
+// Parse a single line scalar or flow collection
+func parseYAMLScalar(text string) (interface{}, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	switch text[0] {
+	case '"', '\'', '[', '{':
+		value, rest, err := parseYAMLFlow(text)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("unexpected %q after value", rest)
+		}
+		return value, nil
+	case '&', '*', '!', '%', '@', '`':
+		return nil, fmt.Errorf("unsupported YAML syntax %q", text)
+	}
+	return resolveYAMLPlain(text), nil
+}
+
+// YAML escapes that Go doesn't have, as Go escapes
+var yamlEscapes = map[byte]string{
+	'0':  `\x00`,
+	'/':  `/`,
+	' ':  ` `,
+	'\t': `\t`,
+	'e':  `\x1b`,
+	'N':  `\u0085`,
+	'_':  `\u00a0`,
+	'L':  `\u2028`,
+	'P':  `\u2029`,
+}
+
+// Unquote a double-quoted scalar, translating the YAML escapes first
+func unquoteYAML(quoted string) (string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(quoted); i++ {
+		if quoted[i] != '\\' || i+1 == len(quoted) {
+			builder.WriteByte(quoted[i])
+			continue
+		}
+		if escape, ok := yamlEscapes[quoted[i+1]]; ok {
+			builder.WriteString(escape)
+		} else {
+			builder.WriteString(quoted[i : i+2])
+		}
+		i++
+	}
+	return strconv.Unquote(builder.String())
+}
+
+// Parse a flow node at the start of text, returns the remaining text
+func parseYAMLFlow(text string) (value interface{}, rest string, err error) {
+	text = strings.TrimLeft(text, " ")
+	if text == "" {
+		return nil, "", fmt.Errorf("unexpected end of flow collection")
+	}
+
+	switch text[0] {
+	case '"':
+		end := yamlQuoteEnd(text)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string %s", text)
+		}
+		unquoted, err := unquoteYAML(text[:end])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid string %s", text[:end])
+		}
+		return unquoted, text[end:], nil
+
+	case '\'':
+		end := yamlQuoteEnd(text)
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string %s", text)
+		}
+		return strings.ReplaceAll(text[1:end-1], "''", "'"), text[end:], nil
+
+	case '[':
+		sequence := []interface{}{}
+		rest = strings.TrimLeft(text[1:], " ")
+		for !strings.HasPrefix(rest, "]") {
+			var item interface{}
+			if item, rest, err = parseYAMLFlow(rest); err != nil {
+				return nil, "", err
+			}
+			sequence = append(sequence, item)
+			if rest, err = yamlFlowSeparator(rest, ']'); err != nil {
+				return nil, "", err
+			}
+		}
+		return sequence, rest[1:], nil
+
+	case '{':
+		mapping := New[string, interface{}]()
+		rest = strings.TrimLeft(text[1:], " ")
+		for !strings.HasPrefix(rest, "}") {
+			var key, item interface{}
+			if key, rest, err = parseYAMLFlow(rest); err != nil {
+				return nil, "", err
+			}
+			rest = strings.TrimLeft(rest, " ")
+			if !strings.HasPrefix(rest, ":") {
+				return nil, "", fmt.Errorf("expecting ':' in flow mapping")
+			}
+			if item, rest, err = parseYAMLFlow(rest[1:]); err != nil {
+				return nil, "", err
+			}
+			if _, ok := mapping.Get(fmt.Sprint(key)); ok {
+				return nil, "", fmt.Errorf("duplicate mapping key %q", fmt.Sprint(key))
+			}
+			mapping.Set(fmt.Sprint(key), item)
+			if rest, err = yamlFlowSeparator(rest, '}'); err != nil {
+				return nil, "", err
+			}
+		}
+		return mapping, rest[1:], nil
+	}
+
+	// Plain scalar inside a flow collection, ends at an indicator
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		if strings.ContainsRune(",]}", rune(text[i])) ||
+			(text[i] == ':' && (i+1 == len(text) || strings.ContainsRune(" ,]}", rune(text[i+1])))) {
+			end = i
+			break
+		}
+	}
+	plain := strings.TrimSpace(text[:end])
+	if plain == "" {
+		return nil, "", fmt.Errorf("missing value in flow collection")
+	}
+	return resolveYAMLPlain(plain), text[end:], nil
+}
+
+// Consume the separator after a flow collection item, leaving the closing
+// delimiter in place.
+func yamlFlowSeparator(text string, closing byte) (string, error) {
+	text = strings.TrimLeft(text, " ")
+	switch {
+	case text == "":
+		return "", fmt.Errorf("unterminated flow collection")
+	case text[0] == ',':
+		return strings.TrimLeft(text[1:], " "), nil
+	case text[0] == closing:
+		return text, nil
+	}
+	return "", fmt.Errorf("unexpected %q in flow collection", text)
+}
+
+// Resolve the type of a plain scalar following the YAML core schema
+func resolveYAMLPlain(text string) interface{} {
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	if yamlIntRegexp.MatchString(text) {
+		if n, err := strconv.ParseInt(text, 10, 0); err == nil {
+			return int(n)
+		}
+	}
+	if strings.HasPrefix(text, "0x") {
+		if n, err := strconv.ParseInt(text[2:], 16, 0); err == nil {
+			return int(n)
+		}
+	}
+	if strings.HasPrefix(text, "0o") {
+		if n, err := strconv.ParseInt(text[2:], 8, 0); err == nil {
+			return int(n)
+		}
+	}
+	if yamlFloatRegexp.MatchString(text) {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
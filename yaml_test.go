@@ -0,0 +1,301 @@
+package orderedmap
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const yamlConfig = `# Service configuration
+name: billing
+replicas: 3
+ratio: 0.5
+enabled: true
+owner: null
+tags:
+  - payments
+  - "1.0"
+  - ""
+server:
+  port: 8080
+  host: localhost:8080
+  routes:
+    - path: /invoices
+      methods:
+        - GET
+        - POST
+    - path: /health
+      methods: []
+empty: {}
+`
+
+func TestYAMLRoundTrip(t *testing.T) {
+	om := New[string, interface{}]()
+	if err := om.DecodeYAML(strings.NewReader(yamlConfig)); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for key := range om.Keys() {
+		keys = append(keys, key)
+	}
+	if strings.Join(keys, " ") != "name replicas ratio enabled owner tags server empty" {
+		t.Error("Keys out of order ", keys)
+	}
+
+	mapHasKey(t, toAny(om), "replicas", 3)
+	mapHasKey(t, toAny(om), "ratio", 0.5)
+	mapHasKey(t, toAny(om), "enabled", true)
+	mapHasKey(t, toAny(om), "owner", nil)
+
+	value, _ := om.Get("server")
+	server, ok := value.(*OrderedMap[string, interface{}])
+	if !ok {
+		t.Fatal("Expecting a nested OrderedMap, received ", value)
+	}
+	if key, value, _ := server.GetLast(); key != "routes" || fmt.Sprintf("%T", value) != "[]interface {}" {
+		t.Error("Invalid nested mapping ", key, value)
+	}
+
+	var buffer bytes.Buffer
+	if err := om.EncodeYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(yamlConfig, "# Service configuration\n", "", 1)
+	if buffer.String() != expected {
+		t.Error(fmt.Sprintf("Expecting:\n%v\nReceived:\n%v", expected, buffer.String()))
+	}
+}
+
+func TestYAMLTypedValues(t *testing.T) {
+	type limits struct {
+		CPU    string `json:"cpu"`
+		Memory int    `json:"memory"`
+	}
+
+	om := New[string, limits]()
+	document := "web:\n  cpu: 500m\n  memory: 256\nworker: {cpu: \"2\", memory: 1024}\n"
+	if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := om.Get("worker"); value.CPU != "2" || value.Memory != 1024 {
+		t.Error("Invalid flow mapping decoding ", value)
+	}
+
+	var buffer bytes.Buffer
+	if err := om.EncodeYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	expected := "web:\n  cpu: 500m\n  memory: 256\nworker:\n  cpu: \"2\"\n  memory: 1024\n"
+	if buffer.String() != expected {
+		t.Error(fmt.Sprintf("Expecting:\n%v\nReceived:\n%v", expected, buffer.String()))
+	}
+
+	ints := New[int, []string]()
+	if err := ints.DecodeYAML(strings.NewReader("20: [a, 'b''c']\n10:\n- d\n")); err != nil {
+		t.Fatal(err)
+	}
+	if key, value, _ := ints.GetFirst(); key != 20 || fmt.Sprint(value) != "[a b'c]" {
+		t.Error("Invalid integer keys decoding ", key, value)
+	}
+	if key, value, _ := ints.GetLast(); key != 10 || fmt.Sprint(value) != "[d]" {
+		t.Error("Invalid indentless sequence decoding ", key, value)
+	}
+}
+
+func TestYAMLBlockScalars(t *testing.T) {
+	document := `literal: |
+  line one
+    indented
+
+  last
+folded: >-
+  folded
+  text
+
+  paragraph
+keep: |+
+  kept
+
+strip: |-
+  # not a comment
+tabbed: |
+  	code
+`
+	om := New[string, string]()
+	if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"literal": "line one\n  indented\n\nlast\n",
+		"folded":  "folded text\nparagraph",
+		"keep":    "kept\n\n",
+		"strip":   "# not a comment",
+		"tabbed":  "\tcode\n",
+	}
+	for key, text := range expected {
+		if value, _ := om.Get(key); value != text {
+			t.Error(fmt.Sprintf("%v: expecting %q received %q", key, text, value))
+		}
+	}
+
+	// Multi-line strings are encoded quoted and read back unchanged
+	var buffer bytes.Buffer
+	if err := om.EncodeYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	decoded := New[string, string]()
+	if err := decoded.DecodeYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range om.All() {
+		if text, _ := decoded.Get(key); text != value {
+			t.Error(fmt.Sprintf("%v: expecting %q received %q", key, value, text))
+		}
+	}
+}
+
+func TestYAMLSpecialFloats(t *testing.T) {
+	om := New[string, float64]()
+	document := "inf: .inf\nneg: -.Inf\nnan: .nan\n"
+	if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+		t.Fatal(err)
+	}
+	inf, _ := om.Get("inf")
+	neg, _ := om.Get("neg")
+	nan, _ := om.Get("nan")
+	if !math.IsInf(inf, 1) || !math.IsInf(neg, -1) || !math.IsNaN(nan) {
+		t.Error("Invalid special floats ", om)
+	}
+
+	lists := New[string, []float32]()
+	if err := lists.DecodeYAML(strings.NewReader("values: [1.5, .inf]\n")); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := lists.Get("values"); len(values) != 2 || values[0] != 1.5 || !math.IsInf(float64(values[1]), 1) {
+		t.Error("Invalid special floats in a sequence ", values)
+	}
+
+	if err := New[string, int]().DecodeYAML(strings.NewReader("a: .inf\n")); err == nil {
+		t.Error("Decoded an infinity into an int")
+	}
+}
+
+func TestYAMLEscapes(t *testing.T) {
+	document := `slash: "x\/y"
+escape: "\e[0m"
+null: "a\0b"
+next-line: "\N"
+nbsp: "\_"
+separators: "\L\P"
+space: "\ \	"
+unicode: "\u00e9\x41"
+`
+	om := New[string, string]()
+	if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"slash":      "x/y",
+		"escape":     "\x1b[0m",
+		"null":       "a\x00b",
+		"next-line":  "\u0085",
+		"nbsp":       "\u00a0",
+		"separators": "\u2028\u2029",
+		"space":      " \t",
+		"unicode":    "éA",
+	}
+	for key, text := range expected {
+		if value, _ := om.Get(key); value != text {
+			t.Error(fmt.Sprintf("%v: expecting %q received %q", key, text, value))
+		}
+	}
+
+	if err := om.DecodeYAML(strings.NewReader(`key: "\q"`)); err == nil {
+		t.Error("Decoded an invalid escape")
+	}
+}
+
+func TestYAMLSequences(t *testing.T) {
+	om := New[string, interface{}]()
+	document := "matrix:\n  - - 1\n    - 2\n  - [3, 4]\n  -\n  - key: value\n"
+	if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+		t.Fatal(err)
+	}
+	value, _ := om.Get("matrix")
+	if fmt.Sprint(value) != "[[1 2] [3 4] <nil> OrderedMap[key:value]]" {
+		t.Error("Invalid sequence decoding ", value)
+	}
+
+	var buffer bytes.Buffer
+	if err := om.EncodeYAML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	expected := "matrix:\n  - - 1\n    - 2\n  - - 3\n    - 4\n  - null\n  - key: value\n"
+	if buffer.String() != expected {
+		t.Error(fmt.Sprintf("Expecting:\n%v\nReceived:\n%v", expected, buffer.String()))
+	}
+}
+
+func TestYAMLEmpty(t *testing.T) {
+	om := New[string, int]()
+	for _, document := range []string{"", "# comment\n", "---\n", "null\n"} {
+		if err := om.DecodeYAML(strings.NewReader(document)); err != nil {
+			t.Error(fmt.Sprintf("%q: %v", document, err))
+		}
+	}
+	if om.Len() != 0 {
+		t.Error("Empty documents added keys")
+	}
+
+	var buffer bytes.Buffer
+	if err := om.EncodeYAML(&buffer); err != nil || buffer.String() != "{}\n" {
+		t.Error("Invalid empty map encoding ", buffer.String(), err)
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	documents := []string{
+		"- a\n- b\n",
+		"key: value\n  bad: indent\n",
+		"key: &anchor value\n",
+		"key: [unterminated\n",
+		"key:\n\t- tab\n",
+		"key: |\n\ttab\n",
+		"a: b: c\n",
+		"- a: b: c\n",
+		"a: 1\nb: 2\na: 3\n",
+		"a:\n  b: 1\n  b: 2\n",
+		"a: {b: 1, b: 2}\n",
+		"key: value\nplain scalar\n",
+		"text: multi\n  line\n",
+	}
+
+	for _, document := range documents {
+		om := New[string, interface{}]()
+		if err := om.DecodeYAML(strings.NewReader(document)); err == nil {
+			t.Error(fmt.Sprintf("%q: expecting error, decoded %v", document, om))
+		}
+	}
+
+	// Errors point to the offending line
+	om := New[string, interface{}]()
+	err := om.DecodeYAML(strings.NewReader("a: 1\nb: x: y\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Error("Expecting an error on line 2, received ", err)
+	}
+	err = om.DecodeYAML(strings.NewReader("a: 1\nb: 2\n\na: 3\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "duplicate") {
+		t.Error("Expecting a duplicate key error on line 4, received ", err)
+	}
+
+	// Value type mismatch
+	ints := New[string, int]()
+	if err := ints.DecodeYAML(strings.NewReader("key: text\n")); err == nil {
+		t.Error("Decoded a string into an int value")
+	}
+}